@@ -0,0 +1,127 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/mdhender/queenie/internal/config"
+	"github.com/mdhender/queenie/internal/services/archive"
+	"github.com/mdhender/queenie/internal/services/solver"
+	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var globalArchive struct {
+	format string
+}
+
+var cmdArchive = &cobra.Command{
+	Use:   "archive",
+	Short: "work with the archive of past puzzles",
+	Long: `Work with the archive of past puzzles.
+
+The archive is a JSON file named by archive.file in the configuration,
+or by --archive.`,
+}
+
+var cmdArchiveImport = &cobra.Command{
+	Use:   "import file...",
+	Short: "load puzzle history files into the archive",
+	Long: `Load puzzle history files into the archive.
+
+JSON files hold a list of puzzles (or an object with a "puzzles" list),
+each with date, center, hex, answers, status, and notes fields.
+CSV files have a header row naming the same columns; answers are
+separated by spaces, commas, or semicolons.
+
+Puzzles replace any already archived for the same date. Every puzzle in
+every file is checked before the archive is changed, so a bad row leaves
+the archive as it was.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var puzzles []archive.ArchivedPuzzle
+		for _, name := range args {
+			p, err := readHistory(name, globalArchive.format)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			puzzles = append(puzzles, p...)
+		}
+
+		store, err := archive.Open(globalBase.cfg.Archive.File)
+		if err != nil {
+			return err
+		}
+		added, updated, err := store.Put(puzzles...)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: %d added, %d updated\n", globalBase.cfg.Archive.File, added, updated)
+		return nil
+	},
+}
+
+// readHistory reads a history file. The format is "json" or "csv";
+// if it is empty, the file's extension decides.
+func readHistory(name, format string) ([]archive.ArchivedPuzzle, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".")
+	}
+	fp, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+	switch format {
+	case "json":
+		return archive.ReadJSON(fp)
+	case "csv":
+		return archive.ReadCSV(fp)
+	}
+	return nil, fmt.Errorf("unknown format %q (use --format json or csv)", format)
+}
+
+// archiveHistory returns the archived puzzles in the form the solver uses
+// to train its classifier. Puzzles with invalid letters are skipped.
+func archiveHistory(store *archive.Store) []solver.History {
+	var history []solver.History
+	for _, p := range store.List() {
+		puzzle, err := solver.NewPuzzle(p.Center, p.Hex)
+		if err != nil {
+			continue
+		}
+		history = append(history, solver.History{
+			Puzzle:   puzzle,
+			Answers:  p.Answers,
+			Complete: p.Status == archive.StatusComplete,
+		})
+	}
+	return history
+}
+
+func init() {
+	cmdArchive.PersistentFlags().String("archive", config.Default().Archive.File, "archive of past puzzles")
+	cmdArchiveImport.Flags().StringVar(&globalArchive.format, "format", "", "format of the history files: json or csv (default from the extension)")
+
+	cmdArchive.AddCommand(cmdArchiveImport)
+	cmdBase.AddCommand(cmdArchive)
+}
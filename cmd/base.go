@@ -21,9 +21,10 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/mdhender/queenie/internal/config"
 	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
+	"github.com/spf13/pflag"
 	"log"
 )
 
@@ -32,9 +33,22 @@ var globalBase struct {
 	VerboseFlag bool
 	ConfigFile  string // configuration file from command line flag
 
-	envPrefix  string // value to prepend when converting flags to env variables
-	cfgName    string // default configuration file name
-	homeFolder string // derived path to home directory
+	envPrefix  string         // value to prepend when converting flags to env variables
+	cfgName    string         // default configuration file name
+	homeFolder string         // derived path to home directory
+	cfg        *config.Config // effective configuration, loaded before any command runs
+}
+
+// configFlags maps configuration keys to the flags that override them.
+// Commands that don't define a flag just don't override that key.
+var configFlags = map[string]string{
+	"server.host":           "host",
+	"server.port":           "port",
+	"server.maxBodyBytes":   "max-body-bytes",
+	"server.requireJson":    "require-json",
+	"server.timeout":        "timeout",
+	"server.methodTimeouts": "method-timeout",
+	"archive.file":          "archive",
 }
 
 // cmdBase represents the base command when called without any subcommands
@@ -42,13 +56,21 @@ var cmdBase = &cobra.Command{
 	Use:   "queenie",
 	Short: "Spelling Bee helper",
 	Long:  `queenie provides services to help solve the Spelling Bee.`,
+	// errors are reported once, by Execute, without the usage text
+	SilenceErrors: true,
+	SilenceUsage:  true,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		// now bind viper and cobra configuration since this hook always runs early
-		return bindConfig(cmd)
+		// load the configuration since this hook always runs early
+		cfg, err := loadConfig(cmd.Flags())
+		if err != nil {
+			return err
+		}
+		globalBase.cfg = cfg
+		return nil
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		log.Printf("%-20s == %q\n", "HOME", globalBase.homeFolder)
-		log.Printf("%-20s == %q\n", "QUEENIE_RC", viper.ConfigFileUsed())
+		log.Printf("%-20s == %q\n", "QUEENIE_RC", globalBase.cfg.ConfigFile)
 	},
 }
 
@@ -58,6 +80,35 @@ func Execute() {
 	cobra.CheckErr(cmdBase.Execute())
 }
 
+// loadConfig loads the configuration from defaults, the configuration file,
+// the environment, and the command's flags, in that order.
+// Like config.Loader.Load, it returns a configuration that is not valid
+// along with the *config.ValidationError, so that it can be shown.
+func loadConfig(flags *pflag.FlagSet) (*config.Config, error) {
+	loader := config.Loader{
+		File:      globalBase.ConfigFile,
+		Name:      globalBase.cfgName,
+		EnvPrefix: globalBase.envPrefix,
+		Flags:     make(map[string]*pflag.Flag),
+	}
+	if globalBase.homeFolder != "" {
+		loader.Paths = append(loader.Paths, globalBase.homeFolder)
+	}
+	for key, name := range configFlags {
+		if f := flags.Lookup(name); f != nil {
+			loader.Flags[key] = f
+		}
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		return cfg, err
+	}
+	if globalBase.VerboseFlag && cfg.ConfigFile != "" {
+		log.Printf("[config] using config file: %q\n", cfg.ConfigFile)
+	}
+	return cfg, nil
+}
+
 func init() {
 	// set the env and config
 	globalBase.envPrefix, globalBase.cfgName = "QUEENIE", ".queenie"
@@ -66,7 +117,7 @@ func init() {
 		globalBase.homeFolder = home
 	}
 
-	cmdBase.PersistentFlags().StringVar(&globalBase.ConfigFile, "config", "", fmt.Sprintf("config file (default is $HOME/%s.json)", globalBase.cfgName))
+	cmdBase.PersistentFlags().StringVar(&globalBase.ConfigFile, "config", "", fmt.Sprintf("config file (default is $HOME/%s.json, .yaml, or .toml)", globalBase.cfgName))
 	cmdBase.PersistentFlags().BoolVar(&globalBase.TestFlag, "test", false, "test mode")
 	cmdBase.PersistentFlags().BoolVar(&globalBase.VerboseFlag, "verbose", false, "verbose mode")
 
@@ -0,0 +1,96 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/mdhender/queenie/internal/oto"
+	"github.com/spf13/cobra"
+	"log"
+	"strings"
+)
+
+var globalCodegen struct {
+	root   string
+	check  bool
+	extras []string
+}
+
+var cmdCodegen = &cobra.Command{
+	Use:   "codegen",
+	Short: "generate service and client code from the definitions",
+	Long: `Generate the service interfaces, HTTP handlers, Go clients, and TypeScript
+clients from the definitions in internal/definition.
+
+With --check, nothing is written and the command fails if any generated
+file is missing or out of date.`,
+	Args: cobra.NoArgs,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// code generation doesn't need the configuration
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targets := append([]oto.Target{}, oto.DefaultTargets...)
+		for _, extra := range globalCodegen.extras {
+			fields := strings.SplitN(extra, "=", 2)
+			if len(fields) != 2 {
+				return fmt.Errorf("template: %q: want template=output", extra)
+			}
+			targets = append(targets, oto.Target{Template: fields[0], Output: fields[1]})
+		}
+
+		files, err := oto.Generate(globalCodegen.root, targets)
+		if err != nil {
+			return err
+		}
+
+		if globalCodegen.check {
+			stale, err := oto.Stale(globalCodegen.root, files)
+			if err != nil {
+				return err
+			}
+			for _, name := range stale {
+				fmt.Printf("stale: %s\n", name)
+			}
+			if len(stale) != 0 {
+				return fmt.Errorf("%d generated files are out of date: run queenie codegen", len(stale))
+			}
+			return nil
+		}
+
+		if err := oto.Write(globalCodegen.root, files); err != nil {
+			return err
+		}
+		if globalBase.VerboseFlag {
+			for _, f := range files {
+				log.Printf("[codegen] wrote %s\n", f.Name)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	cmdCodegen.Flags().StringVar(&globalCodegen.root, "root", "internal", "folder containing the definition folder")
+	cmdCodegen.Flags().BoolVar(&globalCodegen.check, "check", false, "fail if generated files are out of date")
+	cmdCodegen.Flags().StringArrayVar(&globalCodegen.extras, "template", nil, "extra template to render, as template=output; {pkg} in output is replaced by the package name")
+
+	cmdBase.AddCommand(cmdCodegen)
+}
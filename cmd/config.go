@@ -0,0 +1,132 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"github.com/mdhender/queenie/internal/config"
+	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+)
+
+var globalConfig struct {
+	force bool
+}
+
+var cmdConfig = &cobra.Command{
+	Use:   "config",
+	Short: "manage the configuration file",
+	Long:  `Create, show, and validate the configuration file.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// these commands report configuration errors themselves,
+		// so don't let the base command fail before they run.
+		return nil
+	},
+}
+
+var cmdConfigInit = &cobra.Command{
+	Use:   "init [file]",
+	Short: "write a default configuration file",
+	Long: `Write a configuration file containing the default values.
+The extension of the file determines the format (json, yaml, yml, or toml).
+YAML and TOML files include a comment describing each setting.
+The default is $HOME/.queenie.yaml.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := filepath.Join(globalBase.homeFolder, globalBase.cfgName+".yaml")
+		if len(args) == 1 {
+			name = args[0]
+		}
+		if _, err := os.Stat(name); err == nil && !globalConfig.force {
+			return fmt.Errorf("%s: file exists (use --force to overwrite)", name)
+		}
+		if err := config.Write(name, config.Default()); err != nil {
+			return err
+		}
+		fmt.Printf("wrote %s\n", name)
+
+		// warn if the new file will be hidden by another one
+		if len(args) == 0 {
+			if cfg, _ := loadConfig(cmd.Flags()); cfg != nil && cfg.ConfigFile != "" && cfg.ConfigFile != filepath.Clean(name) {
+				fmt.Printf("warning: %s will be used instead of %s\n", cfg.ConfigFile, name)
+			}
+		}
+		return nil
+	},
+}
+
+var cmdConfigShow = &cobra.Command{
+	Use:   "show",
+	Short: "show the effective configuration",
+	Long: `Show the configuration after merging the defaults, the configuration file,
+and the environment, along with where each value came from.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd.Flags())
+		if cfg == nil {
+			return err
+		}
+		if cfg.ConfigFile == "" {
+			fmt.Printf("# no configuration file found\n")
+		} else {
+			fmt.Printf("# configuration file %s\n", cfg.ConfigFile)
+		}
+		for _, key := range config.Keys() {
+			fmt.Printf("%-24s = %-24s # %s\n", key, cfg.Value(key), cfg.Source(key))
+		}
+		// show the values even when they aren't valid, then report the problems
+		return err
+	},
+}
+
+var cmdConfigValidate = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "check a configuration file",
+	Long: `Check a configuration file for unknown keys and invalid values.
+The default is the file the other commands would use.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		loader := config.Loader{File: globalBase.ConfigFile, Name: globalBase.cfgName, Strict: true}
+		if len(args) == 1 {
+			loader.File = args[0]
+		}
+		if loader.File == "" && globalBase.homeFolder != "" {
+			loader.Paths = append(loader.Paths, globalBase.homeFolder)
+		}
+		cfg, err := loader.Load()
+		if err != nil {
+			return err
+		} else if cfg.ConfigFile == "" {
+			return fmt.Errorf("no configuration file found")
+		}
+		fmt.Printf("%s: ok\n", cfg.ConfigFile)
+		return nil
+	},
+}
+
+func init() {
+	cmdConfigInit.Flags().BoolVar(&globalConfig.force, "force", false, "overwrite an existing file")
+
+	cmdConfig.AddCommand(cmdConfigInit)
+	cmdConfig.AddCommand(cmdConfigShow)
+	cmdConfig.AddCommand(cmdConfigValidate)
+	cmdBase.AddCommand(cmdConfig)
+}
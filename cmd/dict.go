@@ -0,0 +1,484 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/mdhender/queenie/internal/config"
+	"github.com/mdhender/queenie/internal/services/archive"
+	"github.com/mdhender/queenie/internal/services/solver"
+	"github.com/mdhender/queenie/internal/wordlist"
+	"github.com/spf13/cobra"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var globalDict struct {
+	bundle struct {
+		out string
+	}
+	compile struct {
+		out string
+	}
+	evaluate struct {
+		words bool
+	}
+}
+
+var cmdDict = &cobra.Command{
+	Use:   "dict",
+	Short: "work with the word lists",
+	Long:  `Work with the word lists the solver uses.`,
+}
+
+var cmdDictEvaluate = &cobra.Command{
+	Use:   "evaluate",
+	Short: "measure the word lists against the archived answers",
+	Long: `Solve every complete puzzle in the archive with the local word lists and
+compare the solver's answers with the official ones.
+
+For each puzzle, and overall, the report shows the false positives (words
+the solver accepts that the game didn't), the false negatives (official
+answers the solver misses), precision (the share of the solver's answers
+that are official), and recall (the share of official answers the solver
+finds).
+
+It then lists the words to add to valid.txt and invalid.txt to fix the
+misses. Words accepted in one puzzle but not in another are listed
+separately, since no change to the lists can fix both.
+
+Puzzles marked partial are skipped because their missing answers would
+look like false positives.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := archive.Open(globalBase.cfg.Archive.File)
+		if err != nil {
+			return err
+		}
+		s, err := newSolverService()
+		if err != nil {
+			return err
+		}
+		files := dictFiles()
+		valid, err := loadWordSet(files.Valid)
+		if err != nil {
+			return err
+		}
+		invalid, err := loadWordSet(files.Invalid)
+		if err != nil {
+			return err
+		}
+
+		var results []evaluation
+		skipped := 0
+		for _, p := range store.List() {
+			if p.Status != archive.StatusComplete {
+				skipped++
+				continue
+			}
+			result, err := evaluate(context.Background(), s, p)
+			if err != nil {
+				return fmt.Errorf("%s: %w", p.Date, err)
+			}
+			results = append(results, result)
+		}
+		if len(results) == 0 {
+			return fmt.Errorf("%s: no complete puzzles to evaluate", globalBase.cfg.Archive.File)
+		}
+		sort.Slice(results, func(i, j int) bool { return results[i].date < results[j].date })
+		writeEvaluation(os.Stdout, results, skipped, valid, invalid, globalDict.evaluate.words)
+		return nil
+	},
+}
+
+var cmdDictReport = &cobra.Command{
+	Use:   "report [file...]",
+	Short: "show how the word lists load",
+	Long: `Load word lists and report, for each, the lines read, the words loaded,
+and the comments, duplicates, and rejected lines, with the reason for each
+rejection. The default is the lists the solver loads, embedded and
+configured.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			s, err := newSolverService()
+			if err != nil {
+				return err
+			}
+			for _, report := range s.Reports() {
+				fmt.Println(report)
+			}
+			return nil
+		}
+		for _, name := range args {
+			_, report, err := wordlist.Load(name)
+			if err != nil {
+				return err
+			}
+			fmt.Println(report)
+		}
+		return nil
+	},
+}
+
+var cmdDictBundle = &cobra.Command{
+	Use:   "bundle",
+	Short: "copy the word lists into the source tree for embedding",
+	Long: `Copy the configured word lists into the folder that is embedded in the
+binary, compressing the dictionary, so that the next build uses them as
+its defaults. Run it from the root of the source tree, then rebuild.
+
+Lists that aren't found are left as they are in the folder.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		files := dictFiles()
+		for _, list := range []struct{ name, embedded string }{
+			{files.Wordlist, "wordlist.txt.gz"},
+			{files.Valid, "valid.txt"},
+			{files.Invalid, "invalid.txt"},
+			{files.Checks, "checks.txt"},
+		} {
+			if list.name == "" {
+				continue
+			}
+			// check that the list loads before replacing the embedded one
+			_, report, err := wordlist.Load(list.name)
+			if err != nil {
+				return err
+			}
+			raw, err := os.ReadFile(list.name)
+			if err != nil {
+				return err
+			}
+			if strings.HasSuffix(list.embedded, ".gz") && !bytes.HasPrefix(raw, []byte{0x1f, 0x8b}) {
+				var buf bytes.Buffer
+				zw, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+				if _, err := zw.Write(raw); err != nil {
+					return err
+				} else if err := zw.Close(); err != nil {
+					return err
+				}
+				raw = buf.Bytes()
+			}
+			out := filepath.Join(globalDict.bundle.out, list.embedded)
+			if err := os.WriteFile(out, raw, 0644); err != nil {
+				return err
+			}
+			fmt.Printf("%s: %d words\n", out, report.Words)
+		}
+		return nil
+	},
+}
+
+var cmdDictCompile = &cobra.Command{
+	Use:   "compile",
+	Short: "compile the word lists into a snapshot for fast startup",
+	Long: `Load the embedded and configured word lists and write them as a binary
+snapshot: the sorted, deduplicated words with their letter masks and tags,
+the curated lists, the frequency ranks, and a hash of the contents.
+
+The solver loads the snapshot (dict.snapshot) instead of the lists as long
+as it is newer than every configured list and was compiled from the word
+lists embedded in this binary, so compile again after editing the lists
+or upgrading queenie.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		files := dictFiles()
+		files.Snapshot = ""
+		s, err := solver.NewService(files)
+		if err != nil {
+			return err
+		}
+
+		// write to a temporary file so that a failure leaves the old snapshot
+		name := globalDict.compile.out
+		if name == "" {
+			name = globalBase.cfg.Dict.Snapshot
+		}
+		tmp, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+		if err := tmp.Chmod(0644); err != nil {
+			tmp.Close()
+			return err
+		}
+		w := bufio.NewWriter(tmp)
+		if err := s.WriteSnapshot(w); err != nil {
+			tmp.Close()
+			return err
+		} else if err := w.Flush(); err != nil {
+			tmp.Close()
+			return err
+		} else if err := tmp.Close(); err != nil {
+			return err
+		} else if err := os.Rename(tmp.Name(), name); err != nil {
+			return err
+		}
+		fmt.Printf("%s: %d words\n", name, s.Words())
+		return nil
+	},
+}
+
+// dictFiles returns the word lists to layer over the embedded ones.
+// Lists at their default names are skipped if they don't exist, even when
+// the name is set in the configuration file (config init writes them all),
+// so a fresh install works from the embedded lists alone. The snapshot
+// is skipped if any of the lists is newer than it or if it is stale.
+func dictFiles() solver.Files {
+	cfg, defaults := globalBase.cfg, config.Default()
+	files := solver.Files{
+		Wordlist:  cfg.Dict.Wordlist,
+		Valid:     cfg.Dict.Valid,
+		Invalid:   cfg.Dict.Invalid,
+		Checks:    cfg.Dict.Checks,
+		Frequency: cfg.Dict.Frequency,
+		Snapshot:  cfg.Dict.Snapshot,
+	}
+	for name, defaultName := range map[*string]string{
+		&files.Wordlist:  defaults.Dict.Wordlist,
+		&files.Valid:     defaults.Dict.Valid,
+		&files.Invalid:   defaults.Dict.Invalid,
+		&files.Checks:    defaults.Dict.Checks,
+		&files.Frequency: defaults.Dict.Frequency,
+		&files.Snapshot:  defaults.Dict.Snapshot,
+	} {
+		if *name != defaultName {
+			continue
+		} else if _, err := os.Stat(*name); err != nil {
+			*name = ""
+		}
+	}
+
+	if files.Snapshot != "" {
+		snap, err := os.Stat(files.Snapshot)
+		if err != nil {
+			// a configured snapshot must exist; let the solver report it
+			return files
+		} else if err := solver.CheckSnapshot(files.Snapshot); errors.Is(err, solver.ErrStaleSnapshot) {
+			if globalBase.VerboseFlag {
+				log.Printf("[dict] %v; not using the snapshot\n", err)
+			}
+			files.Snapshot = ""
+			return files
+		}
+		for _, name := range []string{files.Wordlist, files.Valid, files.Invalid, files.Checks, files.Frequency} {
+			if fi, err := os.Stat(name); name != "" && err == nil && fi.ModTime().After(snap.ModTime()) {
+				if globalBase.VerboseFlag {
+					log.Printf("[dict] %s is newer than %s; not using the snapshot\n", name, files.Snapshot)
+				}
+				files.Snapshot = ""
+				break
+			}
+		}
+	}
+	return files
+}
+
+// newSolverService returns a solver using the embedded word lists
+// with the configured files layered on top.
+func newSolverService() (solver.Service, error) {
+	s, err := solver.NewService(dictFiles())
+	if err != nil {
+		return solver.Service{}, err
+	}
+	if globalBase.VerboseFlag {
+		for _, report := range s.Reports() {
+			log.Printf("[dict] %s\n", report)
+		}
+	}
+	return s, nil
+}
+
+// loadWordSet returns the set of words in a list, or an empty set if
+// the name is empty. The load report is logged in verbose mode.
+func loadWordSet(name string) (map[string]bool, error) {
+	if name == "" {
+		return make(map[string]bool), nil
+	}
+	words, report, err := wordlist.LoadSet(name)
+	if err != nil {
+		return nil, err
+	}
+	if globalBase.VerboseFlag {
+		log.Printf("[dict] %s\n", report)
+	}
+	return words, nil
+}
+
+// evaluation compares the solver's answers for a puzzle with the official ones.
+type evaluation struct {
+	date           string
+	letters        string
+	truePositives  []string // official answers the solver accepts
+	falsePositives []string // solver answers that aren't official
+	falseNegatives []string // official answers the solver misses
+}
+
+// evaluate solves an archived puzzle and compares the answers.
+func evaluate(ctx context.Context, s solver.Service, p archive.ArchivedPuzzle) (evaluation, error) {
+	puzzle, err := solver.NewPuzzle(p.Center, p.Hex)
+	if err != nil {
+		return evaluation{}, err
+	}
+	answers, err := s.Answers(ctx, puzzle)
+	if err != nil {
+		return evaluation{}, err
+	}
+	e := evaluation{date: p.Date, letters: strings.ToUpper(p.Center) + " " + strings.ToUpper(p.Hex)}
+	official := make(map[string]bool)
+	for _, word := range p.Answers {
+		official[word] = true
+	}
+	predicted := make(map[string]bool)
+	for _, word := range answers {
+		predicted[word] = true
+		if official[word] {
+			e.truePositives = append(e.truePositives, word)
+		} else {
+			e.falsePositives = append(e.falsePositives, word)
+		}
+	}
+	for _, word := range p.Answers {
+		if !predicted[word] {
+			e.falseNegatives = append(e.falseNegatives, word)
+		}
+	}
+	sort.Strings(e.truePositives)
+	sort.Strings(e.falsePositives)
+	sort.Strings(e.falseNegatives)
+	return e, nil
+}
+
+// ratio returns n / (n + m) as a percentage, or 100 if both are zero.
+func ratio(n, m int) float64 {
+	if n+m == 0 {
+		return 100
+	}
+	return 100 * float64(n) / float64(n+m)
+}
+
+// writeEvaluation writes the report for the evaluated puzzles.
+// If words is true, each puzzle's misses are listed under it.
+func writeEvaluation(w io.Writer, results []evaluation, skipped int, valid, invalid map[string]bool, words bool) {
+	fmt.Fprintf(w, "%-10s  %-8s  %8s  %6s  %4s  %4s  %9s  %6s\n", "date", "letters", "official", "solver", "fp", "fn", "precision", "recall")
+	tp, fp, fn := 0, 0, 0
+	for _, e := range results {
+		official, answers := len(e.truePositives)+len(e.falseNegatives), len(e.truePositives)+len(e.falsePositives)
+		fmt.Fprintf(w, "%-10s  %-8s  %8d  %6d  %4d  %4d  %8.1f%%  %5.1f%%\n", e.date, strings.ReplaceAll(e.letters, " ", ""),
+			official, answers, len(e.falsePositives), len(e.falseNegatives),
+			ratio(len(e.truePositives), len(e.falsePositives)), ratio(len(e.truePositives), len(e.falseNegatives)))
+		if words {
+			if len(e.falsePositives) != 0 {
+				fmt.Fprintf(w, "    not accepted: %s\n", strings.Join(e.falsePositives, " "))
+			}
+			if len(e.falseNegatives) != 0 {
+				fmt.Fprintf(w, "    missed:       %s\n", strings.Join(e.falseNegatives, " "))
+			}
+		}
+		tp, fp, fn = tp+len(e.truePositives), fp+len(e.falsePositives), fn+len(e.falseNegatives)
+	}
+	fmt.Fprintf(w, "%-10s  %-8s  %8d  %6d  %4d  %4d  %8.1f%%  %5.1f%%\n", "overall", fmt.Sprintf("%d", len(results)),
+		tp+fn, tp+fp, fp, fn, ratio(tp, fp), ratio(tp, fn))
+	if skipped != 0 {
+		fmt.Fprintf(w, "(%d partial puzzles skipped)\n", skipped)
+	}
+
+	toValid, toInvalid, conflicting := sortMisses(results, valid, invalid)
+	writeWordList(w, "add to valid.txt", toValid, invalid, " (remove from invalid.txt)")
+	writeWordList(w, "add to invalid.txt", toInvalid, valid, " (remove from valid.txt)")
+	writeWordList(w, "accepted in one puzzle but not another", conflicting, nil, "")
+}
+
+// sortMisses sorts the misses into the list each word belongs in.
+// Missed answers go to valid.txt and words that aren't official answers
+// go to invalid.txt, unless the list has them already. A word that isn't
+// accepted in one puzzle but is an answer in another can't be fixed by
+// either list, so it is returned as conflicting instead.
+func sortMisses(results []evaluation, valid, invalid map[string]bool) (toValid, toInvalid, conflicting []string) {
+	official, missed, rejected := make(map[string]bool), make(map[string]bool), make(map[string]bool)
+	for _, e := range results {
+		for _, word := range e.truePositives {
+			official[word] = true
+		}
+		for _, word := range e.falseNegatives {
+			official[word], missed[word] = true, true
+		}
+		for _, word := range e.falsePositives {
+			rejected[word] = true
+		}
+	}
+	for word := range missed {
+		if !rejected[word] && !valid[word] {
+			toValid = append(toValid, word)
+		}
+	}
+	for word := range rejected {
+		if official[word] {
+			conflicting = append(conflicting, word)
+		} else if !invalid[word] {
+			toInvalid = append(toInvalid, word)
+		}
+	}
+	sort.Strings(toValid)
+	sort.Strings(toInvalid)
+	sort.Strings(conflicting)
+	return toValid, toInvalid, conflicting
+}
+
+// writeWordList writes a heading and the words under it, one per line.
+// Words in the other list are marked with note. Empty lists aren't written.
+func writeWordList(w io.Writer, heading string, words []string, other map[string]bool, note string) {
+	if len(words) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s (%d):\n", heading, len(words))
+	for _, word := range words {
+		if other[word] {
+			fmt.Fprintf(w, "  %s%s\n", word, note)
+		} else {
+			fmt.Fprintf(w, "  %s\n", word)
+		}
+	}
+}
+
+func init() {
+	cmdDict.PersistentFlags().String("archive", config.Default().Archive.File, "archive of past puzzles")
+	cmdDictEvaluate.Flags().BoolVar(&globalDict.evaluate.words, "words", false, "list each puzzle's false positives and negatives")
+
+	cmdDictBundle.Flags().StringVar(&globalDict.bundle.out, "out", filepath.Join("internal", "services", "solver", "data"), "folder of embedded word lists")
+
+	cmdDictCompile.Flags().StringVarP(&globalDict.compile.out, "output", "o", "", "snapshot file to write (default from dict.snapshot)")
+
+	cmdDict.AddCommand(cmdDictBundle)
+	cmdDict.AddCommand(cmdDictCompile)
+	cmdDict.AddCommand(cmdDictEvaluate)
+	cmdDict.AddCommand(cmdDictReport)
+	cmdBase.AddCommand(cmdDict)
+}
@@ -0,0 +1,79 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortMisses(t *testing.T) {
+	results := []evaluation{
+		{
+			date:           "2022-05-01",
+			truePositives:  []string{"couch", "cotton"},
+			falsePositives: []string{"cutch", "mutch"},
+			falseNegatives: []string{"mouth", "notch"},
+		},
+		{
+			// touch is an answer here but not accepted in the first puzzle
+			date:           "2022-05-02",
+			truePositives:  []string{"couch", "touch"},
+			falsePositives: []string{"cotton"},
+			falseNegatives: []string{"hutch"},
+		},
+		{
+			date:           "2022-05-03",
+			truePositives:  []string{"mutch"},
+			falsePositives: []string{"touch"},
+		},
+	}
+	valid := map[string]bool{"notch": true}
+	invalid := map[string]bool{"cutch": true}
+
+	toValid, toInvalid, conflicting := sortMisses(results, valid, invalid)
+	if want := []string{"hutch", "mouth"}; !reflect.DeepEqual(toValid, want) {
+		t.Errorf("to valid: got %q, want %q", toValid, want)
+	}
+	if toInvalid != nil {
+		t.Errorf("to invalid: got %q, want none", toInvalid)
+	}
+	if want := []string{"cotton", "mutch", "touch"}; !reflect.DeepEqual(conflicting, want) {
+		t.Errorf("conflicting: got %q, want %q", conflicting, want)
+	}
+
+	// a true positive is never suggested for either list
+	for _, word := range append(toValid, toInvalid...) {
+		if word == "couch" {
+			t.Errorf("true positive %q suggested", word)
+		}
+	}
+
+	toValid, toInvalid, conflicting = sortMisses(results[:1], nil, nil)
+	if want := []string{"mouth", "notch"}; !reflect.DeepEqual(toValid, want) {
+		t.Errorf("one puzzle: to valid: got %q, want %q", toValid, want)
+	}
+	if want := []string{"cutch", "mutch"}; !reflect.DeepEqual(toInvalid, want) {
+		t.Errorf("one puzzle: to invalid: got %q, want %q", toInvalid, want)
+	}
+	if conflicting != nil {
+		t.Errorf("one puzzle: conflicting: got %q, want none", conflicting)
+	}
+}
@@ -0,0 +1,171 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/mdhender/queenie/internal/hintspage"
+	"github.com/mdhender/queenie/internal/services/solver"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+var cmdHints = &cobra.Command{
+	Use:   "hints",
+	Short: "work with the official hints page",
+	Long:  `Work with the official Spelling Bee hints page.`,
+}
+
+var cmdHintsImport = &cobra.Command{
+	Use:   "import page.html",
+	Short: "compare a saved hints page with the solver",
+	Long: `Read a hints page saved from the browser and compare its counts with
+the solver's answers for the same puzzle, using the local word lists.
+
+Each count that disagrees is listed with the official value, the solver's
+value, and the difference. A positive difference means the word lists
+have words the game doesn't accept; a negative one means they're missing
+words the game does accept.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fp, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer fp.Close()
+		page, err := hintspage.Parse(fp)
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+		puzzle, err := solver.NewPuzzle(page.Center, page.Hex)
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+
+		s, err := newSolverService()
+		if err != nil {
+			return err
+		}
+		hints, err := s.AnswerHints(context.Background(), puzzle)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Puzzle: %s %s\n", strings.ToUpper(page.Center), strings.ToUpper(page.Hex))
+		fmt.Printf("Official: %d words, %d points, %d pangrams\n", page.Hints.Words, page.Hints.Points, page.Hints.Pangrams)
+		fmt.Printf("Solver:   %d words, %d points, %d pangrams\n", hints.Words, hints.Points, hints.Pangrams)
+		diffs := hints.Diff(page.Hints)
+		if len(diffs) == 0 {
+			fmt.Printf("\nAll counts agree.\n")
+			return nil
+		}
+		fmt.Printf("\n%-18s %8s %8s %6s\n", "count", "official", "solver", "diff")
+		for _, d := range diffs {
+			fmt.Printf("%-18s %8d %8d %+6d\n", d.What, d.Expected, d.Actual, d.Actual-d.Expected)
+		}
+		return nil
+	},
+}
+
+var globalHintsInfer struct {
+	all bool
+}
+
+var cmdHintsInfer = &cobra.Command{
+	Use:   "infer page.html",
+	Short: "work out which words the official list probably has",
+	Long: `Read a hints page saved from the browser and work out which candidate
+words are probably in the official answer list.
+
+Words in the valid list are taken as accepted and words in the invalid
+list as rejected. The rest are chosen to match the official grid,
+two-letter list, pangram count, and point total, and are ranked by how
+likely they are to be accepted. Words the counts settle are left out
+unless --all is given.
+
+Counts that the word lists can't match, such as a cell that needs more
+words than the dictionary has, are reported before the words.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fp, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer fp.Close()
+		page, err := hintspage.Parse(fp)
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+		puzzle, err := solver.NewPuzzle(page.Center, page.Hex)
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+
+		s, err := newSolverService()
+		if err != nil {
+			return err
+		}
+		likelihoods, conflicts, err := s.Infer(context.Background(), puzzle, page.Hints)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Puzzle: %s %s\n", strings.ToUpper(page.Center), strings.ToUpper(page.Hex))
+		for _, conflict := range conflicts {
+			fmt.Printf("conflict: %s\n", conflict)
+		}
+		accepted, rejected, ambiguous := 0, 0, 0
+		for _, l := range likelihoods {
+			switch {
+			case l.Probability >= 1:
+				accepted++
+			case l.Probability <= 0:
+				rejected++
+			default:
+				ambiguous++
+			}
+		}
+		fmt.Printf("%d accepted, %d rejected, %d uncertain\n", accepted, rejected, ambiguous)
+		if ambiguous == 0 && !globalHintsInfer.all {
+			return nil
+		}
+		fmt.Println()
+		for _, l := range likelihoods {
+			if !globalHintsInfer.all && (l.Probability >= 1 || l.Probability <= 0) {
+				continue
+			}
+			known := ""
+			if l.Known != "" {
+				known = " (" + l.Known + ")"
+			}
+			fmt.Printf("%5.1f%%  %s%s\n", 100*l.Probability, l.Word, known)
+		}
+		return nil
+	},
+}
+
+func init() {
+	cmdHints.AddCommand(cmdHintsImport)
+	cmdHints.AddCommand(cmdHintsInfer)
+	cmdHintsInfer.Flags().BoolVar(&globalHintsInfer.all, "all", false, "list the words the counts settle too")
+	cmdBase.AddCommand(cmdHints)
+}
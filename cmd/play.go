@@ -0,0 +1,291 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/mdhender/queenie/internal/services/solver"
+	"github.com/spf13/cobra"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+var globalPlay struct {
+	resume string
+}
+
+var cmdPlay = &cobra.Command{
+	Use:   "play [center hex]",
+	Short: "play a puzzle in the terminal",
+	Long: `Play a puzzle in the terminal using the local word lists.
+Enter a word to guess it, or one of the commands:
+
+  /shuffle        shuffle the outer letters
+  /hints          show the hint grid for the words not yet found
+  /found          list the words found so far
+  /rank           show the score and the points needed for each rank
+  /save [file]    save the game to a file
+  /help           show the commands
+  /quit           leave the game
+
+Use --resume to continue a saved game.`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var g *playGame
+		if globalPlay.resume != "" {
+			if len(args) != 0 {
+				return fmt.Errorf("--resume doesn't take puzzle letters")
+			}
+			var err error
+			if g, err = loadPlayGame(globalPlay.resume); err != nil {
+				return err
+			}
+		} else {
+			if len(args) == 0 {
+				return fmt.Errorf("want center and hex letters or --resume")
+			}
+			request, err := parsePuzzleArgs(args)
+			if err != nil {
+				return err
+			}
+			g = &playGame{Center: request.Center, Hex: request.Hex}
+		}
+		if err := g.start(context.Background()); err != nil {
+			return err
+		}
+		return g.run(os.Stdin, os.Stdout)
+	},
+}
+
+// playGame is the state of a game. The exported fields are saved.
+type playGame struct {
+	Center string    `json:"center"`
+	Hex    string    `json:"hex"`
+	Found  []string  `json:"found"`
+	Saved  time.Time `json:"saved"`
+
+	file    string // where the game was loaded from or last saved to
+	solver  solver.Service
+	puzzle  solver.Puzzle
+	order   []rune          // the outer letters, in display order
+	answers map[string]bool // the words the solver accepts
+	found   map[string]bool
+	total   int // the points for all the answers
+	points  int // the points for the words found
+}
+
+// loadPlayGame reads a saved game.
+func loadPlayGame(name string) (*playGame, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	g := &playGame{file: name}
+	if err := json.Unmarshal(b, g); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return g, nil
+}
+
+// start solves the puzzle and scores any words already found.
+func (g *playGame) start(ctx context.Context) error {
+	var err error
+	if g.puzzle, err = solver.NewPuzzle(g.Center, g.Hex); err != nil {
+		return err
+	}
+	g.order = append([]rune{}, g.puzzle.Hex[:]...)
+
+	if g.solver, err = newSolverService(); err != nil {
+		return err
+	}
+	response, err := g.solver.Solve(ctx, solver.PuzzleRequest{Center: g.Center, Hex: g.Hex})
+	if err != nil {
+		return err
+	}
+	// the answers are the words that Check will accept
+	g.answers = make(map[string]bool)
+	for _, word := range response.Words {
+		check, err := g.solver.Check(ctx, solver.CheckRequest{Center: g.Center, Hex: g.Hex, Word: word})
+		if err != nil {
+			return err
+		} else if check.Accepted {
+			g.answers[word] = true
+			g.total += check.Score
+		}
+	}
+
+	found := g.Found
+	g.Found, g.found = nil, make(map[string]bool)
+	for _, word := range found {
+		if g.answers[word] && !g.found[word] {
+			g.found[word] = true
+			g.Found = append(g.Found, word)
+			g.points += g.puzzle.Score(word)
+		}
+	}
+	return nil
+}
+
+// run reads guesses and commands until the input ends or the player quits.
+func (g *playGame) run(r io.Reader, w io.Writer) error {
+	g.showHive(w)
+	g.showScore(w)
+	scanner := bufio.NewScanner(r)
+	for {
+		_, _ = fmt.Fprint(w, "> ")
+		if !scanner.Scan() {
+			_, _ = fmt.Fprintln(w)
+			return scanner.Err()
+		}
+		input := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if input == "" {
+			continue
+		} else if !strings.HasPrefix(input, "/") {
+			g.guess(w, input)
+			continue
+		}
+
+		fields := strings.Fields(input)
+		switch fields[0] {
+		case "/shuffle":
+			rand.Shuffle(len(g.order), func(i, j int) { g.order[i], g.order[j] = g.order[j], g.order[i] })
+			g.showHive(w)
+		case "/hints":
+			var remaining []string
+			for word := range g.answers {
+				if !g.found[word] {
+					remaining = append(remaining, word)
+				}
+			}
+			_ = writeHints(w, solver.NewHints(g.puzzle, remaining))
+		case "/found":
+			found := append([]string{}, g.Found...)
+			sort.Strings(found)
+			_, _ = fmt.Fprintf(w, "%d words: %s\n", len(found), strings.Join(found, " "))
+		case "/rank":
+			for _, rank := range solver.Ranks(g.total) {
+				marker := " "
+				if g.points >= rank.Points {
+					marker = "*"
+				}
+				_, _ = fmt.Fprintf(w, "%s %-10s %4d\n", marker, rank.Name, rank.Points)
+			}
+			g.showScore(w)
+		case "/save":
+			name := g.file
+			if len(fields) > 1 {
+				name = fields[1]
+			}
+			if name == "" {
+				_, _ = fmt.Fprintln(w, "usage: /save file")
+			} else if err := g.save(name); err != nil {
+				_, _ = fmt.Fprintf(w, "save: %v\n", err)
+			} else {
+				_, _ = fmt.Fprintf(w, "saved to %s\n", name)
+			}
+		case "/help":
+			_, _ = fmt.Fprintln(w, "commands: /shuffle /hints /found /rank /save [file] /help /quit")
+		case "/quit", "/exit":
+			return nil
+		default:
+			_, _ = fmt.Fprintf(w, "unknown command %q (try /help)\n", fields[0])
+		}
+	}
+}
+
+// guess checks a word and scores it if it's new.
+func (g *playGame) guess(w io.Writer, word string) {
+	check, err := g.solver.Check(context.Background(), solver.CheckRequest{Center: g.Center, Hex: g.Hex, Word: word, Found: g.Found})
+	if err != nil {
+		_, _ = fmt.Fprintf(w, "check: %v\n", err)
+		return
+	}
+	switch check.Reason {
+	case solver.ReasonTooShort:
+		_, _ = fmt.Fprintln(w, "too short: words must have at least four letters")
+		return
+	case solver.ReasonBadLetter:
+		_, _ = fmt.Fprintln(w, "bad letter: use only the letters in the hive")
+		return
+	case solver.ReasonMissingCenter:
+		_, _ = fmt.Fprintf(w, "missing center letter: words must use %q\n", strings.ToUpper(string(g.puzzle.Center)))
+		return
+	case solver.ReasonUnknownWord, solver.ReasonKnownRejected:
+		_, _ = fmt.Fprintln(w, "not in word list")
+		return
+	case solver.ReasonDuplicate:
+		_, _ = fmt.Fprintln(w, "already found")
+		return
+	}
+
+	g.found[word] = true
+	g.Found = append(g.Found, word)
+	g.points += check.Score
+	if check.Pangram {
+		_, _ = fmt.Fprintf(w, "pangram! +%d\n", check.Score)
+	} else {
+		_, _ = fmt.Fprintf(w, "+%d\n", check.Score)
+	}
+	g.showScore(w)
+}
+
+// showHive draws the letters as a honeycomb with the center letter in brackets.
+func (g *playGame) showHive(w io.Writer) {
+	up := func(r rune) string { return strings.ToUpper(string(r)) }
+	_, _ = fmt.Fprintf(w, "\n      %s   %s\n", up(g.order[0]), up(g.order[1]))
+	_, _ = fmt.Fprintf(w, "    %s  [%s]  %s\n", up(g.order[2]), up(g.puzzle.Center), up(g.order[3]))
+	_, _ = fmt.Fprintf(w, "      %s   %s\n\n", up(g.order[4]), up(g.order[5]))
+}
+
+// showScore writes the score, rank, and points needed for the next rank.
+func (g *playGame) showScore(w io.Writer) {
+	rank, next, hasNext := solver.RankOf(g.points, g.total)
+	_, _ = fmt.Fprintf(w, "%s: %d points, %d of %d words", rank.Name, g.points, len(g.Found), len(g.answers))
+	if hasNext {
+		_, _ = fmt.Fprintf(w, " (%d to %s)", next.Points-g.points, next.Name)
+	}
+	_, _ = fmt.Fprintln(w)
+}
+
+// save writes the game to a file so that it can be resumed.
+func (g *playGame) save(name string) error {
+	g.Saved = time.Now().UTC()
+	b, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	} else if err := os.WriteFile(name, b, 0644); err != nil {
+		return err
+	}
+	g.file = name
+	return nil
+}
+
+func init() {
+	cmdPlay.Flags().StringVar(&globalPlay.resume, "resume", "", "resume a saved game")
+
+	cmdBase.AddCommand(cmdPlay)
+}
@@ -0,0 +1,131 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/mdhender/queenie/internal/services/solver"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"strings"
+)
+
+var globalProgress struct {
+	file string
+}
+
+var cmdProgress = &cobra.Command{
+	Use:   "progress [center [hex]]",
+	Short: "show progress from pasted found words",
+	Long: `Read the text copied from the game's "You have found N words" panel
+(or a share message) and show the score, rank, and hints for the words
+that remain. The text is read from standard input unless --file is given.
+
+The puzzle letters are inferred from the found words when possible.
+Give the center letter, or all of the letters, on the command line if
+the found words don't pin them down.`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var request solver.ParseFoundRequest
+		if len(args) == 1 && len([]rune(args[0])) == 1 {
+			// just the center letter; the hex letters are inferred
+			request.Center = args[0]
+		} else if len(args) != 0 {
+			puzzle, err := parsePuzzleArgs(args)
+			if err != nil {
+				return err
+			}
+			request.Center, request.Hex = puzzle.Center, puzzle.Hex
+		}
+
+		var r io.Reader = os.Stdin
+		if globalProgress.file != "" {
+			fp, err := os.Open(globalProgress.file)
+			if err != nil {
+				return err
+			}
+			defer fp.Close()
+			r = fp
+		}
+		text, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		request.Text = string(text)
+
+		s, err := newSolverService()
+		if err != nil {
+			return err
+		}
+		response, err := s.ParseFound(context.Background(), request)
+		if err != nil {
+			return err
+		}
+		return writeProgress(os.Stdout, response)
+	},
+}
+
+// writeProgress writes the score and rank followed by the remaining hint grid.
+func writeProgress(w io.Writer, response *solver.ParseFoundResponse) error {
+	p := response.Progress
+	_, _ = fmt.Fprintf(w, "Puzzle: %s %s\n", strings.ToUpper(response.Center), strings.ToUpper(response.Hex))
+	_, _ = fmt.Fprintf(w, "Found:  %d of %d words", p.Words, p.TotalWords)
+	if response.Claimed != 0 && response.Claimed != len(response.Found) {
+		_, _ = fmt.Fprintf(w, " (the text says %d, %d were read)", response.Claimed, len(response.Found))
+	}
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintf(w, "Rank:   %s, %d of %d points", p.Rank, p.Points, p.TotalPoints)
+	if p.NextRank != "" {
+		_, _ = fmt.Fprintf(w, " (%d to %s)", p.PointsToNextRank, p.NextRank)
+	}
+	_, _ = fmt.Fprintln(w)
+	if len(p.Rejected) != 0 {
+		_, _ = fmt.Fprintf(w, "Not in the word list: %s\n", strings.Join(p.Rejected, " "))
+	}
+
+	// the remaining counts use the same layout as the hints page
+	hints := &solver.Hints{
+		Words:      p.RemainingWords,
+		Points:     p.RemainingPoints,
+		Pangrams:   p.RemainingPangrams,
+		Grid:       make(map[rune]map[int]int),
+		TwoLetters: make(map[string]int),
+	}
+	for _, gc := range p.RemainingGrid {
+		letter := []rune(gc.Letter)[0]
+		if hints.Grid[letter] == nil {
+			hints.Grid[letter] = make(map[int]int)
+		}
+		hints.Grid[letter][gc.Length] = gc.Count
+	}
+	for _, pc := range p.RemainingTwoLetters {
+		hints.TwoLetters[pc.Prefix] = pc.Count
+	}
+	_, _ = fmt.Fprintf(w, "\nRemaining ")
+	return writeHints(w, hints)
+}
+
+func init() {
+	cmdProgress.Flags().StringVar(&globalProgress.file, "file", "", "read the pasted text from a file")
+
+	cmdBase.AddCommand(cmdProgress)
+}
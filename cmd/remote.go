@@ -0,0 +1,304 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	solverclient "github.com/mdhender/queenie/internal/clients/solver"
+	"github.com/mdhender/queenie/internal/services/solver"
+	"github.com/spf13/cobra"
+	"io"
+	"log"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+var globalRemote struct {
+	server string
+	wait   time.Duration
+	curate struct {
+		all     bool
+		valid   string
+		invalid string
+	}
+}
+
+var cmdRemote = &cobra.Command{
+	Use:   "remote",
+	Short: "call a running server",
+	Long: `Call the services on a running Queenie server.
+The default server is built from the server host and port in the configuration.`,
+}
+
+var cmdRemoteSolve = &cobra.Command{
+	Use:   "solve center hex",
+	Short: "solve a puzzle on the server",
+	Long:  `Solve a puzzle using the word lists on a running server.`,
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		puzzle, words, err := remoteSolve(args)
+		if err != nil {
+			return err
+		}
+		return writeSolution(os.Stdout, puzzle, words)
+	},
+}
+
+var cmdRemoteHints = &cobra.Command{
+	Use:   "hints center hex",
+	Short: "show the hint grid for a puzzle",
+	Long: `Show the hint grid for a puzzle using the word lists on a running server:
+word and point totals, counts by first letter and length, and two-letter starts.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hints, err := remoteHints(args)
+		if err != nil {
+			return err
+		}
+		return writeHints(os.Stdout, hints)
+	},
+}
+
+var cmdRemoteCurate = &cobra.Command{
+	Use:   "curate center hex",
+	Short: "list words that need curating",
+	Long: `List the words the server returns for a puzzle that are not yet in the
+local valid or invalid lists, so they can be added to one or the other.
+With --all, every word is listed with a marker: + for valid, - for invalid,
+and ? for unverified.
+
+The local lists are the configured dict.valid and dict.invalid files
+unless --valid or --invalid is given. A list at its default name that
+doesn't exist is treated as empty.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		files := dictFiles()
+		if globalRemote.curate.valid != "" {
+			files.Valid = globalRemote.curate.valid
+		}
+		if globalRemote.curate.invalid != "" {
+			files.Invalid = globalRemote.curate.invalid
+		}
+		valid, err := loadWordSet(files.Valid)
+		if err != nil {
+			return err
+		}
+		invalid, err := loadWordSet(files.Invalid)
+		if err != nil {
+			return err
+		}
+		_, words, err := remoteSolve(args)
+		if err != nil {
+			return err
+		}
+		sort.Strings(words)
+		for _, word := range words {
+			marker := "?"
+			if valid[word] {
+				marker = "+"
+			} else if invalid[word] {
+				marker = "-"
+			}
+			if marker == "?" || globalRemote.curate.all {
+				fmt.Printf("%s%s\n", marker, word)
+			}
+		}
+		return nil
+	},
+}
+
+var cmdRemoteVersions = &cobra.Command{
+	Use:   "versions",
+	Short: "show the server's dictionary versions",
+	Long: `Show the hash, load time, and word count of the word lists the server
+is using, followed by every version it has loaded since it started.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		response, err := solverclient.NewSolverService(remoteClient()).Versions(context.Background(), solverclient.VersionsRequest{})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("current  %s  %s  %d words\n", response.Current.Hash, response.Current.LoadedAt, response.Current.Words)
+		for _, v := range response.Seen {
+			fmt.Printf("seen     %s  %s  %d words\n", v.Hash, v.LoadedAt, v.Words)
+		}
+		return nil
+	},
+}
+
+// remoteClient returns a client for the server's oto routes.
+func remoteClient() *solverclient.Client {
+	client := solverclient.New(remoteServer())
+	client.HTTPClient.Timeout = globalRemote.wait
+	if globalBase.VerboseFlag {
+		client.Debug = func(s string) { log.Printf("[remote] %s\n", s) }
+	}
+	return client
+}
+
+// remoteServer returns the URL of the server's oto routes.
+func remoteServer() string {
+	if globalRemote.server != "" {
+		return strings.TrimSuffix(globalRemote.server, "/") + "/oto/"
+	}
+	host := globalBase.cfg.Server.Host
+	if host == "" {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, globalBase.cfg.Server.Port) + "/oto/"
+}
+
+// remoteSolve asks the server for the words for the puzzle in args.
+func remoteSolve(args []string) (solver.Puzzle, []string, error) {
+	request, err := parsePuzzleArgs(args)
+	if err != nil {
+		return solver.Puzzle{}, nil, err
+	}
+	puzzle, err := solver.NewPuzzle(request.Center, request.Hex)
+	if err != nil {
+		return solver.Puzzle{}, nil, err
+	}
+
+	response, err := solverclient.NewSolverService(remoteClient()).Solve(context.Background(), solverclient.PuzzleRequest{
+		Center:  request.Center,
+		Hex:     request.Hex,
+		MaxRank: globalSolve.maxRank,
+		Exclude: globalSolve.exclude,
+	})
+	if err != nil {
+		return solver.Puzzle{}, nil, err
+	}
+	return puzzle, response.Words, nil
+}
+
+// remoteHints asks the server for the hints for the puzzle in args.
+// The server builds them from its answers, so they match the local hints command.
+func remoteHints(args []string) (*solver.Hints, error) {
+	request, err := parsePuzzleArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	response, err := solverclient.NewSolverService(remoteClient()).Hints(context.Background(), solverclient.HintsRequest{
+		Center: request.Center,
+		Hex:    request.Hex,
+	})
+	if err != nil {
+		return nil, err
+	}
+	hints := &solver.Hints{
+		Words:           response.Words,
+		Points:          response.Points,
+		Pangrams:        response.Pangrams,
+		PerfectPangrams: response.PerfectPangrams,
+		Grid:            make(map[rune]map[int]int),
+		TwoLetters:      make(map[string]int),
+	}
+	for _, count := range response.Grid {
+		if count.Letter == "" {
+			continue
+		}
+		letter := []rune(count.Letter)[0]
+		if hints.Grid[letter] == nil {
+			hints.Grid[letter] = make(map[int]int)
+		}
+		hints.Grid[letter][count.Length] = count.Count
+	}
+	for _, count := range response.TwoLetters {
+		hints.TwoLetters[count.Prefix] = count.Count
+	}
+	return hints, nil
+}
+
+// writeHints writes the hint grid in the same layout as the official hints page.
+func writeHints(w io.Writer, h *solver.Hints) error {
+	_, _ = fmt.Fprintf(w, "WORDS: %d, POINTS: %d, PANGRAMS: %d", h.Words, h.Points, h.Pangrams)
+	if h.PerfectPangrams != 0 {
+		_, _ = fmt.Fprintf(w, " (%d Perfect)", h.PerfectPangrams)
+	}
+	_, _ = fmt.Fprintln(w)
+	if h.Words == 0 {
+		return nil
+	}
+
+	lengths := h.Lengths()
+	_, _ = fmt.Fprintf(w, "\n   ")
+	for _, n := range lengths {
+		_, _ = fmt.Fprintf(w, " %3d", n)
+	}
+	_, _ = fmt.Fprintf(w, "   Σ\n")
+	totals := make(map[int]int)
+	for _, letter := range h.Letters() {
+		sum := 0
+		_, _ = fmt.Fprintf(w, "%s: ", strings.ToUpper(string(letter)))
+		for _, n := range lengths {
+			count := h.Grid[letter][n]
+			totals[n] += count
+			sum += count
+			if count == 0 {
+				_, _ = fmt.Fprintf(w, "   -")
+			} else {
+				_, _ = fmt.Fprintf(w, " %3d", count)
+			}
+		}
+		_, _ = fmt.Fprintf(w, " %3d\n", sum)
+	}
+	_, _ = fmt.Fprintf(w, "Σ: ")
+	for _, n := range lengths {
+		_, _ = fmt.Fprintf(w, " %3d", totals[n])
+	}
+	_, _ = fmt.Fprintf(w, " %3d\n", h.Words)
+
+	var starts []string
+	for start := range h.TwoLetters {
+		starts = append(starts, start)
+	}
+	sort.Strings(starts)
+	_, _ = fmt.Fprintf(w, "\nTwo letter list:\n")
+	for i, start := range starts {
+		if i > 0 && starts[i-1][0] != start[0] {
+			_, _ = fmt.Fprintln(w)
+		} else if i > 0 {
+			_, _ = fmt.Fprint(w, " ")
+		}
+		_, _ = fmt.Fprintf(w, "%s-%d", strings.ToUpper(start), h.TwoLetters[start])
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+func init() {
+	cmdRemote.PersistentFlags().StringVar(&globalRemote.server, "server", "", "server URL, e.g. http://localhost:8080")
+	cmdRemote.PersistentFlags().DurationVar(&globalRemote.wait, "wait", 10*time.Second, "time to wait for the server")
+
+	addSolutionFlags(cmdRemoteSolve)
+	cmdRemoteCurate.Flags().BoolVar(&globalRemote.curate.all, "all", false, "list every word with its status")
+	cmdRemoteCurate.Flags().StringVar(&globalRemote.curate.valid, "valid", "", "list of words known to be accepted (default dict.valid)")
+	cmdRemoteCurate.Flags().StringVar(&globalRemote.curate.invalid, "invalid", "", "list of words known to be rejected (default dict.invalid)")
+
+	cmdRemote.AddCommand(cmdRemoteSolve)
+	cmdRemote.AddCommand(cmdRemoteHints)
+	cmdRemote.AddCommand(cmdRemoteCurate)
+	cmdRemote.AddCommand(cmdRemoteVersions)
+	cmdBase.AddCommand(cmdRemote)
+}
@@ -0,0 +1,73 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/mdhender/queenie/internal/services/solver"
+	"github.com/spf13/cobra"
+)
+
+var globalSearch solver.SearchRequest
+
+var cmdSearch = &cobra.Command{
+	Use:   "search",
+	Short: "search the dictionary",
+	Long: `Search the local word lists. Every option that is set must match.
+
+	queenie search --prefix cot
+	queenie search --pattern 'c?tt?n'
+	queenie search --pattern '*tion' --max 8
+	queenie search --letters nottoc --exact
+	queenie search --center c --hex hmnotu --min 7`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := newSolverService()
+		if err != nil {
+			return err
+		}
+		response, err := s.Search(context.Background(), globalSearch)
+		if err != nil {
+			return err
+		}
+		for _, word := range response.Words {
+			fmt.Println(word)
+		}
+		if response.Truncated {
+			fmt.Printf("(stopped at %d words)\n", globalSearch.Limit)
+		}
+		return nil
+	},
+}
+
+func init() {
+	cmdSearch.Flags().StringVar(&globalSearch.Prefix, "prefix", "", "start of the word")
+	cmdSearch.Flags().StringVar(&globalSearch.Pattern, "pattern", "", "whole word, with ? for any letter and * for any run of letters")
+	cmdSearch.Flags().StringVar(&globalSearch.Letters, "letters", "", "letters to make the word from, with ? for a blank")
+	cmdSearch.Flags().BoolVar(&globalSearch.Exact, "exact", false, "use every one of --letters")
+	cmdSearch.Flags().IntVar(&globalSearch.MinLength, "min", 0, "shortest word")
+	cmdSearch.Flags().IntVar(&globalSearch.MaxLength, "max", 0, "longest word")
+	cmdSearch.Flags().StringVar(&globalSearch.Center, "center", "", "puzzle center letter, to restrict words to a puzzle")
+	cmdSearch.Flags().StringVar(&globalSearch.Hex, "hex", "", "puzzle hex letters")
+	cmdSearch.Flags().IntVar(&globalSearch.Limit, "limit", 0, "most words to list (0 for no limit)")
+
+	cmdBase.AddCommand(cmdSearch)
+}
@@ -23,6 +23,7 @@ import (
 	"context"
 	"github.com/mdhender/queenie/internal/config"
 	"github.com/mdhender/queenie/internal/otohttp"
+	"github.com/mdhender/queenie/internal/services/archive"
 	"github.com/mdhender/queenie/internal/services/greeter"
 	"github.com/mdhender/queenie/internal/services/solver"
 	"github.com/spf13/cobra"
@@ -46,10 +47,7 @@ var cmdServe = &cobra.Command{
 	Short: "start the API server",
 	Long:  `Start the API server.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg := &config.Config{ConfigFile: globalBase.ConfigFile}
-		if err := config.Read(cfg); err != nil {
-			log.Fatal(err)
-		}
+		cfg := globalBase.cfg
 		if globalBase.VerboseFlag {
 			log.Printf("[serve] %-30s == %q\n", "config", cfg.ConfigFile)
 			log.Printf("[serve] %-30s == %q\n", "host", cfg.Server.Host)
@@ -69,6 +67,14 @@ var cmdServe = &cobra.Command{
 		if globalServe.debug.cors {
 			options = append(options, otohttp.WithDebugCors(true))
 		}
+		options = append(options, otohttp.WithMaxBodyBytes(cfg.Server.MaxBodyBytes))
+		options = append(options, otohttp.WithRequireJSON(cfg.Server.RequireJSON))
+		options = append(options, otohttp.WithTimeout(time.Duration(cfg.Server.Timeout)))
+		for _, value := range cfg.Server.MethodTimeouts {
+			// the configuration has already been validated
+			method, d, _ := config.ParseMethodTimeout(value)
+			options = append(options, otohttp.WithMethodTimeout(method, d))
+		}
 
 		s, err := otohttp.NewServer(cfg, otohttp.Options(options...))
 		if err != nil {
@@ -76,11 +82,21 @@ var cmdServe = &cobra.Command{
 		}
 
 		greeter.RegisterGreeterService(s, greeter.Service{})
-		if solverService, err := solver.NewService(); err != nil {
+		store, err := archive.Open(cfg.Archive.File)
+		if err != nil {
+			log.Fatal(err)
+		}
+		archive.RegisterArchiveService(s, archive.NewService(store))
+		solverService, err := newSolverService()
+		if err != nil {
 			log.Fatal(err)
-		} else {
-			solver.RegisterSolverService(s, solverService)
 		}
+		// train the acceptance classifier on the archive too
+		if solverService, err = solverService.WithHistory(ctx, archiveHistory(store)); err != nil {
+			log.Fatal(err)
+		}
+		solver.RegisterSolverService(s, solverService)
+		solver.RegisterWordSearchService(s, solverService)
 
 		// run server in a go routine that we can cancel
 		go func() {
@@ -122,5 +138,15 @@ var cmdServe = &cobra.Command{
 func init() {
 	cmdServe.Flags().BoolVar(&globalServe.debug.cors, "debug-cors", false, "enable CORS debugging")
 
+	// these flags override the configuration (see configFlags)
+	defaults := config.Default()
+	cmdServe.Flags().String("host", defaults.Server.Host, "host to listen on")
+	cmdServe.Flags().String("port", defaults.Server.Port, "port to listen on")
+	cmdServe.Flags().Int64("max-body-bytes", defaults.Server.MaxBodyBytes, "reject request bodies larger than this")
+	cmdServe.Flags().Duration("timeout", time.Duration(defaults.Server.Timeout), "default deadline for service methods (0 for none)")
+	cmdServe.Flags().StringSlice("method-timeout", nil, "deadline for a service method, e.g. SolverService.Solve=2s")
+	cmdServe.Flags().Bool("require-json", defaults.Server.RequireJSON, "reject requests without a JSON Content-Type")
+	cmdServe.Flags().String("archive", defaults.Archive.File, "archive of past puzzles")
+
 	cmdBase.AddCommand(cmdServe)
 }
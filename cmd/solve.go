@@ -0,0 +1,269 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/mdhender/queenie/internal/services/solver"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var globalSolve struct {
+	sort    string
+	group   bool
+	format  string
+	maxRank int
+	exclude []string
+}
+
+var cmdSolve = &cobra.Command{
+	Use:   "solve center hex",
+	Short: "solve a puzzle without a server",
+	Long: `Solve a puzzle using the local word lists.
+The puzzle can be given as the center letter and the six hex letters
+(queenie solve c hmnotu) or as seven letters with the center first
+(queenie solve chmnotu).`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		request, err := parsePuzzleArgs(args)
+		if err != nil {
+			return err
+		}
+		puzzle, err := solver.NewPuzzle(request.Center, request.Hex)
+		if err != nil {
+			return err
+		}
+
+		s, err := newSolverService()
+		if err != nil {
+			return err
+		}
+		request.MaxRank = globalSolve.maxRank
+		request.Exclude = globalSolve.exclude
+		response, err := s.Solve(context.Background(), request)
+		if err != nil {
+			return err
+		}
+
+		return writeSolution(os.Stdout, puzzle, response.Words)
+	},
+}
+
+// parsePuzzleArgs accepts either the center and hex letters as two
+// arguments or all seven letters, center first, as one argument.
+func parsePuzzleArgs(args []string) (solver.PuzzleRequest, error) {
+	var request solver.PuzzleRequest
+	if len(args) == 2 {
+		request.Center, request.Hex = args[0], args[1]
+	} else if letters := []rune(args[0]); len(args) == 1 && len(letters) == 7 {
+		request.Center, request.Hex = string(letters[0]), string(letters[1:])
+	} else {
+		return request, fmt.Errorf("want center and hex letters or seven letters, got %q", strings.Join(args, " "))
+	}
+	return request, nil
+}
+
+// writeSolution sorts and writes the words using the --sort, --group,
+// and --format flags.
+func writeSolution(w io.Writer, puzzle solver.Puzzle, words []string) error {
+	var entries []solveEntry
+	for _, word := range words {
+		entries = append(entries, solveEntry{
+			Word:    word,
+			Length:  len([]rune(word)),
+			Score:   puzzle.Score(word),
+			Pangram: puzzle.IsPangram(word),
+		})
+	}
+	if err := sortEntries(entries, globalSolve.sort, globalSolve.group); err != nil {
+		return err
+	}
+
+	switch globalSolve.format {
+	case "text":
+		return writeSolveText(w, entries, globalSolve.group)
+	case "json":
+		return writeSolveJSON(w, entries, globalSolve.group)
+	case "csv":
+		return writeSolveCSV(w, entries)
+	case "markdown", "md":
+		return writeSolveMarkdown(w, entries, globalSolve.group)
+	}
+	return fmt.Errorf("format: want text, json, csv, or markdown, got %q", globalSolve.format)
+}
+
+// solveEntry is a single word in the solution.
+type solveEntry struct {
+	Word    string `json:"word"`
+	Length  int    `json:"length"`
+	Score   int    `json:"score"`
+	Pangram bool   `json:"pangram,omitempty"`
+}
+
+// letter returns the first letter of the word.
+func (e solveEntry) letter() string {
+	for _, r := range e.Word {
+		return string(r)
+	}
+	return ""
+}
+
+// sortEntries orders the words by alpha, length (longest first),
+// or score (highest first). Ties are broken alphabetically.
+// When grouping, words are first ordered by their first letter.
+func sortEntries(entries []solveEntry, by string, group bool) error {
+	var less func(a, b solveEntry) bool
+	switch by {
+	case "alpha", "alphabetical":
+		less = func(a, b solveEntry) bool { return a.Word < b.Word }
+	case "length":
+		less = func(a, b solveEntry) bool {
+			if a.Length != b.Length {
+				return a.Length > b.Length
+			}
+			return a.Word < b.Word
+		}
+	case "score":
+		less = func(a, b solveEntry) bool {
+			if a.Score != b.Score {
+				return a.Score > b.Score
+			}
+			return a.Word < b.Word
+		}
+	default:
+		return fmt.Errorf("sort: want alpha, length, or score, got %q", by)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if group && entries[i].letter() != entries[j].letter() {
+			return entries[i].letter() < entries[j].letter()
+		}
+		return less(entries[i], entries[j])
+	})
+	return nil
+}
+
+// groupEntries splits sorted entries into runs that share a first letter.
+func groupEntries(entries []solveEntry) (groups [][]solveEntry) {
+	for i, e := range entries {
+		if i == 0 || e.letter() != entries[i-1].letter() {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], e)
+	}
+	return groups
+}
+
+func writeSolveText(w io.Writer, entries []solveEntry, group bool) error {
+	if !group {
+		for _, e := range entries {
+			if _, err := fmt.Fprintln(w, e.Word); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for i, g := range groupEntries(entries) {
+		if i > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		_, _ = fmt.Fprintf(w, "%s (%d)\n", strings.ToUpper(g[0].letter()), len(g))
+		for _, e := range g {
+			if _, err := fmt.Fprintf(w, "  %s\n", e.Word); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeSolveJSON(w io.Writer, entries []solveEntry, group bool) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if !group {
+		if entries == nil {
+			entries = []solveEntry{}
+		}
+		return enc.Encode(entries)
+	}
+	groups := make(map[string][]solveEntry)
+	for _, g := range groupEntries(entries) {
+		groups[g[0].letter()] = g
+	}
+	return enc.Encode(groups)
+}
+
+func writeSolveCSV(w io.Writer, entries []solveEntry) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"word", "length", "score", "pangram"})
+	for _, e := range entries {
+		_ = cw.Write([]string{e.Word, strconv.Itoa(e.Length), strconv.Itoa(e.Score), strconv.FormatBool(e.Pangram)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeSolveMarkdown(w io.Writer, entries []solveEntry, group bool) error {
+	table := func(entries []solveEntry) {
+		_, _ = fmt.Fprintln(w, "| Word | Length | Score | Pangram |")
+		_, _ = fmt.Fprintln(w, "|------|-------:|------:|:-------:|")
+		for _, e := range entries {
+			pangram := ""
+			if e.Pangram {
+				pangram = "yes"
+			}
+			_, _ = fmt.Fprintf(w, "| %s | %d | %d | %s |\n", e.Word, e.Length, e.Score, pangram)
+		}
+	}
+	if !group {
+		table(entries)
+		return nil
+	}
+	for i, g := range groupEntries(entries) {
+		if i > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		_, _ = fmt.Fprintf(w, "## %s\n\n", strings.ToUpper(g[0].letter()))
+		table(g)
+	}
+	return nil
+}
+
+// addSolutionFlags adds the flags shared by the solve commands.
+func addSolutionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&globalSolve.sort, "sort", "alpha", "sort by alpha, length, or score")
+	cmd.Flags().BoolVar(&globalSolve.group, "group", false, "group words by first letter")
+	cmd.Flags().StringVar(&globalSolve.format, "format", "text", "output format: text, json, csv, or markdown")
+	cmd.Flags().StringSliceVar(&globalSolve.exclude, "exclude", nil, "leave out words with these tags, e.g. proper,acronym")
+	cmd.Flags().IntVar(&globalSolve.maxRank, "max-rank", 0, "leave out words less common than this frequency rank (0 for no limit)")
+}
+
+func init() {
+	addSolutionFlags(cmdSolve)
+
+	cmdBase.AddCommand(cmdSolve)
+}
@@ -17,12 +17,22 @@
  *
  */
 
+// Package config loads and validates the Queenie configuration.
 package config
 
 import (
 	"encoding/json"
+	"fmt"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
@@ -30,25 +40,382 @@ type Config struct {
 	Server     struct {
 		Host string `json:"host,omitempty"`
 		Port string `json:"port"`
+		// MaxBodyBytes is the largest request body the server will accept.
+		MaxBodyBytes int64 `json:"maxBodyBytes"`
+		// RequireJSON rejects requests without a JSON Content-Type.
+		RequireJSON bool `json:"requireJson"`
+		// Timeout is the default deadline for service methods. Zero means no deadline.
+		Timeout Duration `json:"timeout"`
+		// MethodTimeouts are deadlines for individual methods, given as
+		// "Service.Method=duration", e.g. "SolverService.Solve=2s".
+		MethodTimeouts []string `json:"methodTimeouts"`
 	} `json:"server"`
+	Archive struct {
+		// File is the JSON file holding the archive of past puzzles.
+		File string `json:"file"`
+	} `json:"archive"`
+	Dict struct {
+		// Wordlist, Valid, Invalid, Checks, and Frequency are files layered
+		// over the word lists embedded in the binary. Empty names are skipped.
+		Wordlist  string `json:"wordlist"`
+		Valid     string `json:"valid"`
+		Invalid   string `json:"invalid"`
+		Checks    string `json:"checks"`
+		Frequency string `json:"frequency"`
+		// Snapshot is a compiled snapshot of the lists, used instead of them
+		// if it is newer than all of them.
+		Snapshot string `json:"snapshot"`
+	} `json:"dict"`
+
+	sources map[string]string // key to where the value came from
 }
 
-// Read loads a configuration from file.
-// It returns any errors.
-func Read(c *Config) error {
-	b, err := os.ReadFile(c.ConfigFile)
+// field describes a single configuration key.
+type field struct {
+	key   string
+	help  string
+	value func(c *Config) interface{}
+}
+
+// fields lists every configuration key, in the order they are written.
+// Keep it in sync with Config.
+var fields = []field{
+	{key: "server.host", help: "Host to listen on. Leave empty to listen on all interfaces.",
+		value: func(c *Config) interface{} { return c.Server.Host }},
+	{key: "server.port", help: "Port to listen on.",
+		value: func(c *Config) interface{} { return c.Server.Port }},
+	{key: "server.maxBodyBytes", help: "Largest request body the server will accept. Larger bodies get a 413.",
+		value: func(c *Config) interface{} { return c.Server.MaxBodyBytes }},
+	{key: "server.requireJson", help: "Reject requests that don't send a JSON Content-Type.",
+		value: func(c *Config) interface{} { return c.Server.RequireJSON }},
+	{key: "server.timeout", help: "Default deadline for service methods, e.g. \"5s\". Zero means no deadline.",
+		value: func(c *Config) interface{} { return c.Server.Timeout }},
+	{key: "server.methodTimeouts", help: "Deadlines for individual methods, e.g. [\"SolverService.Solve=2s\"].",
+		value: func(c *Config) interface{} { return c.Server.MethodTimeouts }},
+	{key: "archive.file", help: "JSON file holding the archive of past puzzles. It is created if missing.",
+		value: func(c *Config) interface{} { return c.Archive.File }},
+	{key: "dict.wordlist", help: "Dictionary words added to the embedded list. Skipped if missing, unless set.",
+		value: func(c *Config) interface{} { return c.Dict.Wordlist }},
+	{key: "dict.valid", help: "Words known to be accepted, overriding the embedded lists. Skipped if missing, unless set.",
+		value: func(c *Config) interface{} { return c.Dict.Valid }},
+	{key: "dict.invalid", help: "Words known to be rejected, overriding the embedded lists. Skipped if missing, unless set.",
+		value: func(c *Config) interface{} { return c.Dict.Invalid }},
+	{key: "dict.checks", help: "Words already checked. Skipped if missing, unless set.",
+		value: func(c *Config) interface{} { return c.Dict.Checks }},
+	{key: "dict.frequency", help: "Word frequencies, most common first, or \"word count\" lines. Skipped if missing, unless set.",
+		value: func(c *Config) interface{} { return c.Dict.Frequency }},
+	{key: "dict.snapshot", help: "Compiled snapshot from \"queenie dict compile\", used instead of the lists if it is newer than all of them.",
+		value: func(c *Config) interface{} { return c.Dict.Snapshot }},
+}
+
+// Default returns the default configuration.
+func Default() *Config {
+	c := &Config{}
+	c.Server.Port = "8080"
+	c.Server.MaxBodyBytes = 1024 * 1024
+	c.Server.MethodTimeouts = []string{}
+	c.Archive.File = "archive.json"
+	c.Dict.Wordlist = "wordlist.txt"
+	c.Dict.Valid = "valid.txt"
+	c.Dict.Invalid = "invalid.txt"
+	c.Dict.Checks = "checks.txt"
+	c.Dict.Frequency = "frequency.txt"
+	c.Dict.Snapshot = "dictionary.snap"
+	return c
+}
+
+// Loader loads a configuration.
+// Values are layered: defaults, then the configuration file,
+// then environment variables, then flags.
+type Loader struct {
+	// File is the configuration file to load. It must exist if set.
+	File string
+	// Name is the base name of the file to search for when File is not set.
+	// The extension determines the format (json, yaml, yml, or toml).
+	Name string
+	// Paths are the folders to search for Name.
+	Paths []string
+	// EnvPrefix is prepended to keys to find environment variables.
+	// For example, server.port is read from QUEENIE_SERVER_PORT.
+	EnvPrefix string
+	// Flags are command line flags, keyed by the configuration key
+	// they override. Only flags that were set on the command line are used.
+	Flags map[string]*pflag.Flag
+	// Strict reports unknown keys in the configuration file as errors.
+	Strict bool
+}
+
+// Load returns the merged and validated configuration.
+// If the configuration loads but is not valid, it is returned
+// along with a *ValidationError so that callers can still show it.
+func (l Loader) Load() (*Config, error) {
+	v := viper.New()
+
+	// every key needs a default so that viper knows to look in the environment for it
+	defaults := Default()
+	for _, f := range fields {
+		v.SetDefault(f.key, f.value(defaults))
+	}
+
+	if l.File != "" {
+		v.SetConfigFile(l.File)
+	} else {
+		v.SetConfigName(l.Name)
+		for _, path := range l.Paths {
+			v.AddConfigPath(path)
+		}
+	}
+	if err := v.ReadInConfig(); err != nil {
+		// a missing default file is fine, but the user must have meant a file they named
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok || l.File != "" {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+	}
+
+	if l.EnvPrefix != "" {
+		v.SetEnvPrefix(l.EnvPrefix)
+		v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+		v.AutomaticEnv()
+	}
+
+	for key, flag := range l.Flags {
+		if flag == nil {
+			continue
+		} else if err := v.BindPFlag(key, flag); err != nil {
+			return nil, fmt.Errorf("config: %s: %w", key, err)
+		}
+	}
+
+	c := &Config{sources: make(map[string]string)}
+	if err := v.Unmarshal(c, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.TextUnmarshallerHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	))); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if used := v.ConfigFileUsed(); used != "" {
+		c.ConfigFile = filepath.Clean(used)
+	}
+
+	// remember where each value came from, checking layers from the top down
+	for _, f := range fields {
+		envName := strings.ToUpper(l.EnvPrefix + "_" + strings.ReplaceAll(f.key, ".", "_"))
+		if flag, ok := l.Flags[f.key]; ok && flag != nil && flag.Changed {
+			c.sources[f.key] = "flag --" + flag.Name
+		} else if _, ok := os.LookupEnv(envName); ok && l.EnvPrefix != "" {
+			c.sources[f.key] = "env " + envName
+		} else if v.InConfig(f.key) {
+			c.sources[f.key] = "file " + c.ConfigFile
+		} else {
+			c.sources[f.key] = "default"
+		}
+	}
+
+	err := c.Validate()
+	if l.Strict {
+		if unknown := unknownKeys(v.AllKeys()); len(unknown) != 0 {
+			var problems []string
+			for _, key := range unknown {
+				problems = append(problems, fmt.Sprintf("%s: unknown key", key))
+			}
+			if verr, ok := err.(*ValidationError); ok {
+				problems = append(problems, verr.Problems...)
+			}
+			err = &ValidationError{File: c.ConfigFile, Problems: problems}
+		}
+	}
 	if err != nil {
-		return err
+		return c, err
+	}
+	return c, nil
+}
+
+// Source returns where the value for a key came from:
+// "default", "file <name>", "env <name>", or "flag --<name>".
+// It returns an empty string for configurations that weren't loaded.
+func (c *Config) Source(key string) string {
+	return c.sources[key]
+}
+
+// Keys returns the configuration keys in the order they are written.
+func Keys() []string {
+	var keys []string
+	for _, f := range fields {
+		keys = append(keys, f.key)
+	}
+	return keys
+}
+
+// Value returns the value of a key formatted for display.
+func (c *Config) Value(key string) string {
+	for _, f := range fields {
+		if f.key == key {
+			return formatValue(f.value(c))
+		}
+	}
+	return ""
+}
+
+// unknownKeys returns the keys that aren't part of the configuration.
+// Viper reports keys in lower case, so the comparison is case-insensitive.
+func unknownKeys(keys []string) []string {
+	known := make(map[string]bool)
+	for _, f := range fields {
+		known[strings.ToLower(f.key)] = true
+	}
+	var unknown []string
+	for _, key := range keys {
+		if !known[strings.ToLower(key)] {
+			unknown = append(unknown, key)
+		}
 	}
-	return json.Unmarshal(b, c)
+	sort.Strings(unknown)
+	return unknown
 }
 
-// Write writes a configuration to a JSON file.
+// Validate checks the configuration for errors.
+// It returns a ValidationError listing every problem found.
+func (c *Config) Validate() error {
+	var problems []string
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		problems = append(problems, fmt.Sprintf("server.port: %q is not a valid port number (1-65535)", c.Server.Port))
+	}
+	if strings.Contains(c.Server.Host, ":") && !strings.HasPrefix(c.Server.Host, "[") {
+		problems = append(problems, fmt.Sprintf("server.host: %q must not contain a port", c.Server.Host))
+	}
+	if c.Server.MaxBodyBytes < 1 {
+		problems = append(problems, fmt.Sprintf("server.maxBodyBytes: %d must be positive", c.Server.MaxBodyBytes))
+	}
+	if c.Server.Timeout < 0 {
+		problems = append(problems, fmt.Sprintf("server.timeout: %s must not be negative", c.Server.Timeout))
+	}
+	for _, mt := range c.Server.MethodTimeouts {
+		if _, _, err := ParseMethodTimeout(mt); err != nil {
+			problems = append(problems, fmt.Sprintf("server.methodTimeouts: %v", err))
+		}
+	}
+	if strings.TrimSpace(c.Archive.File) == "" {
+		problems = append(problems, "archive.file: must not be empty")
+	}
+	if len(problems) != 0 {
+		return &ValidationError{File: c.ConfigFile, Problems: problems}
+	}
+	return nil
+}
+
+// ValidationError lists the problems found in a configuration.
+type ValidationError struct {
+	File     string
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	var sb strings.Builder
+	if e.File == "" {
+		sb.WriteString("config: invalid configuration:")
+	} else {
+		sb.WriteString(fmt.Sprintf("config: %s: invalid configuration:", e.File))
+	}
+	for _, problem := range e.Problems {
+		sb.WriteString("\n  ")
+		sb.WriteString(problem)
+	}
+	return sb.String()
+}
+
+// ParseMethodTimeout splits a "Service.Method=duration" value.
+func ParseMethodTimeout(s string) (method string, d time.Duration, err error) {
+	fields := strings.SplitN(s, "=", 2)
+	if len(fields) != 2 || !strings.Contains(fields[0], ".") {
+		return "", 0, fmt.Errorf("%q: want Service.Method=duration", s)
+	}
+	method = strings.TrimSpace(fields[0])
+	if d, err = time.ParseDuration(strings.TrimSpace(fields[1])); err != nil {
+		return "", 0, fmt.Errorf("%q: %w", s, err)
+	} else if d < 0 {
+		return "", 0, fmt.Errorf("%q: duration must not be negative", s)
+	}
+	return method, d, nil
+}
+
+// Write writes a configuration to a file.
+// The extension determines the format. YAML and TOML files include a
+// comment describing each key; JSON doesn't allow comments.
 // It returns any errors.
 func Write(name string, c *Config) error {
-	b, err := json.MarshalIndent(c, "", "  ")
+	var b []byte
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".json":
+		var err error
+		if b, err = json.MarshalIndent(c, "", "  "); err != nil {
+			return err
+		}
+		b = append(b, '\n')
+	case ".toml":
+		b = render(c, "[%s]\n", "# %s\n", "%s = %s\n")
+	case ".yaml", ".yml":
+		b = render(c, "%s:\n", "  # %s\n", "  %s: %s\n")
+	default:
+		return fmt.Errorf("config: %s: unsupported format %q", name, ext)
+	}
+	return ioutil.WriteFile(name, b, 0600)
+}
+
+// render writes the configuration as a sequence of commented sections.
+// The values are formatted so that they're valid in both YAML and TOML.
+func render(c *Config, sectionFmt, commentFmt, valueFmt string) []byte {
+	var sb strings.Builder
+	sb.WriteString("# Queenie configuration.\n")
+	sb.WriteString("# Environment variables (QUEENIE_SERVER_PORT, etc.) and flags override these values.\n")
+	section := ""
+	for _, f := range fields {
+		parts := strings.SplitN(f.key, ".", 2)
+		if parts[0] != section {
+			section = parts[0]
+			sb.WriteString("\n")
+			sb.WriteString(fmt.Sprintf(sectionFmt, section))
+		}
+		sb.WriteString(fmt.Sprintf(commentFmt, f.help))
+		sb.WriteString(fmt.Sprintf(valueFmt, parts[1], formatValue(f.value(c))))
+	}
+	return []byte(sb.String())
+}
+
+// formatValue formats a value as a YAML or TOML literal.
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case Duration:
+		return strconv.Quote(v.String())
+	case []string:
+		var quoted []string
+		for _, s := range v {
+			quoted = append(quoted, strconv.Quote(s))
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// Duration is a time.Duration that is written as a string like "1m30s".
+type Duration time.Duration
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(b []byte) error {
+	t, err := time.ParseDuration(string(b))
 	if err != nil {
 		return err
 	}
-	return ioutil.WriteFile(name, b, 0600)
+	*d = Duration(t)
+	return nil
 }
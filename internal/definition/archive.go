@@ -0,0 +1,109 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package definition
+
+// ArchiveService lists and queries the archive of past puzzles.
+type ArchiveService interface {
+	// List returns the archived puzzles that match the request,
+	// newest first.
+	List(ListRequest) ListResponse
+
+	// Get returns the archived puzzle for a date.
+	Get(GetRequest) GetResponse
+}
+
+// ArchivedPuzzle is a past puzzle and its official answers.
+type ArchivedPuzzle struct {
+	// Date is the day the puzzle was published, as YYYY-MM-DD.
+	// example: "2022-05-01"
+	Date string
+
+	// Center is the required letter.
+	// example: "c"
+	Center string
+
+	// Hex is the remaining six letters.
+	// example: "hmnotu"
+	Hex string
+
+	// Answers is the list of official answers, sorted.
+	// example: ["cotton", "count"]
+	Answers []string
+
+	// Status is "complete" if Answers is the full official list,
+	// or "partial" if only some of the answers are known.
+	// example: "complete"
+	Status string
+
+	// Notes records curation decisions made for the puzzle.
+	// example: "added cottony to valid.txt"
+	Notes string
+}
+
+// ListRequest is the request object for ArchiveService.List
+type ListRequest struct {
+	// From is the earliest date to list, as YYYY-MM-DD.
+	// It is ignored if empty.
+	// example: "2022-01-01"
+	From string
+
+	// To is the latest date to list, as YYYY-MM-DD.
+	// It is ignored if empty.
+	// example: "2022-12-31"
+	To string
+
+	// Status lists only the puzzles with this status.
+	// It is ignored if empty.
+	// example: "complete"
+	Status string
+
+	// Letters lists only the puzzles that use all of these letters.
+	// It is ignored if empty.
+	// example: "ct"
+	Letters string
+
+	// Word lists only the puzzles with this word in their answers.
+	// It is ignored if empty.
+	// example: "cotton"
+	Word string
+
+	// Limit is the most puzzles to return. Zero means no limit.
+	// example: 10
+	Limit int
+}
+
+// ListResponse is the response object containing the matching puzzles.
+type ListResponse struct {
+	// Puzzles is the list of matching puzzles, newest first.
+	Puzzles []ArchivedPuzzle
+}
+
+// GetRequest is the request object for ArchiveService.Get
+type GetRequest struct {
+	// Date is the day the puzzle was published, as YYYY-MM-DD.
+	// example: "2022-05-01"
+	Date string
+}
+
+// GetResponse is the response object containing an archived puzzle.
+type GetResponse struct {
+	// Puzzle is the archived puzzle.
+	Puzzle ArchivedPuzzle
+}
@@ -23,6 +23,31 @@ package definition
 type SolverService interface {
 	// Solve returns a solution.
 	Solve(PuzzleRequest) SolutionResponse
+
+	// Check checks a guess against the puzzle rules and the word lists.
+	Check(CheckRequest) CheckResponse
+
+	// Progress returns the score and rank for the words found so far,
+	// along with hints for the words that remain.
+	Progress(ProgressRequest) ProgressResponse
+
+	// Hints returns the counts from the official hints page for the
+	// puzzle's answers, without the answers themselves.
+	Hints(HintsRequest) HintsResponse
+
+	// ParseFound reads the found words from text copied from the game
+	// and returns the player's progress.
+	ParseFound(ParseFoundRequest) ParseFoundResponse
+
+	// Versions returns the version of the word lists in use and every
+	// version the server has loaded since it started.
+	Versions(VersionsRequest) VersionsResponse
+}
+
+// WordSearchService searches the dictionary.
+type WordSearchService interface {
+	// Search returns the dictionary words that match every part of the query.
+	Search(SearchRequest) SearchResponse
 }
 
 // PuzzleRequest is the request object for SolverService.Solve
@@ -36,6 +61,20 @@ type PuzzleRequest struct {
 	// It must be a string containing exactly six lower-case letters.
 	// example: "hmnotu"
 	Hex string
+
+	// MaxRank is the most obscure word to include, as a frequency rank:
+	// words ranked higher, or missing from the frequency file, are left out.
+	// Words in the valid list are always included. Zero means no limit,
+	// as does a server without a frequency file.
+	// example: 50000
+	MaxRank int
+
+	// Exclude is the list of word tags to leave out: "proper", "acronym",
+	// or any tag from the word list's tags column. Words in the valid list
+	// are always included. Hyphenated words and words with apostrophes
+	// never solve a puzzle, so excluding those tags has no effect.
+	// example: ["proper", "acronym"]
+	Exclude []string
 }
 
 // SolutionResponse is the response object containing the list of known
@@ -44,4 +83,398 @@ type SolutionResponse struct {
 	// Words is the list of known words that satisfy the puzzle.
 	// example: ["cotton", "cottonmouth"]
 	Words []string
+
+	// Frequency is the frequency rank of each word in Words, where 1 is the
+	// most common word and 0 means the word isn't in the frequency file.
+	// It is empty if the server has no frequency file.
+	Frequency []WordFrequency
+
+	// Tags lists the words in Words that the word list tags,
+	// such as proper nouns and acronyms.
+	Tags []WordTags
+
+	// Unverified is the list of words that are in neither the valid
+	// nor the invalid list, with the chance that the game accepts each,
+	// most likely first.
+	Unverified []WordProbability
+
+	// Dictionary identifies the word lists used to answer the request.
+	Dictionary DictionaryVersion
+}
+
+// WordFrequency is how common a word is.
+type WordFrequency struct {
+	// Word is the word.
+	// example: "cotton"
+	Word string
+
+	// Rank is the word's frequency rank, where 1 is the most common word.
+	// It is 0 if the word isn't in the frequency file.
+	// example: 8214
+	Rank int
+}
+
+// WordTags is the tags for a word.
+type WordTags struct {
+	// Word is the word.
+	// example: "boston"
+	Word string
+
+	// Tags is the list of tags for Word.
+	// example: ["proper"]
+	Tags []string
+}
+
+// WordProbability is the chance that the game accepts a word.
+type WordProbability struct {
+	// Word is the word.
+	// example: "cottony"
+	Word string
+
+	// Probability is the chance, from 0 to 1, that the game accepts Word.
+	// It is estimated from the accepted and rejected word lists.
+	// example: 0.82
+	Probability float64
+}
+
+// CheckRequest is the request object for SolverService.Check
+type CheckRequest struct {
+	// Center letter is the required letter.
+	// It must be a single, lower-case letter.
+	// example: "c"
+	Center string
+
+	// Hex letters are the remaining six letters accepted in the solution.
+	// It must be a string containing exactly six lower-case letters.
+	// example: "hmnotu"
+	Hex string
+
+	// Word is the guess to check.
+	// example: "cotton"
+	Word string
+
+	// Found is the list of words already found.
+	// A guess that is in this list is rejected as a duplicate.
+	// example: ["count"]
+	Found []string
+}
+
+// CheckResponse is the response object containing the result of checking a guess.
+type CheckResponse struct {
+	// Accepted is true if the guess is a new, known word that satisfies the puzzle.
+	// example: true
+	Accepted bool
+
+	// Reason explains why the guess was rejected.
+	// It is one of "too-short", "missing-center", "bad-letter", "unknown-word",
+	// "known-rejected", or "duplicate", and is empty for accepted guesses.
+	// example: "missing-center"
+	Reason string
+
+	// Score is the points the guess is worth. It is zero for rejected guesses.
+	// example: 6
+	Score int
+
+	// Pangram is true if the accepted guess uses every letter in the puzzle.
+	// example: false
+	Pangram bool
+
+	// Dictionary identifies the word lists used to answer the request.
+	Dictionary DictionaryVersion
+}
+
+// ProgressRequest is the request object for SolverService.Progress
+type ProgressRequest struct {
+	// Center letter is the required letter.
+	// It must be a single, lower-case letter.
+	// example: "c"
+	Center string
+
+	// Hex letters are the remaining six letters accepted in the solution.
+	// It must be a string containing exactly six lower-case letters.
+	// example: "hmnotu"
+	Hex string
+
+	// Found is the list of words the player has found.
+	// example: ["cotton", "count"]
+	Found []string
+}
+
+// ProgressResponse is the response object containing the player's progress.
+// It never includes the words that remain to be found.
+type ProgressResponse struct {
+	// Points is the score for the words found.
+	// example: 11
+	Points int
+
+	// Words is the number of found words that were accepted.
+	// example: 2
+	Words int
+
+	// Rejected is the list of found words that aren't accepted answers.
+	// example: ["cottonmouths"]
+	Rejected []string
+
+	// Rank is the rank reached with Points.
+	// example: "Good"
+	Rank string
+
+	// NextRank is the next rank to reach. It is empty at Queen Bee.
+	// example: "Solid"
+	NextRank string
+
+	// PointsToNextRank is the number of points needed to reach NextRank.
+	// example: 4
+	PointsToNextRank int
+
+	// TotalPoints is the score for finding every word.
+	// example: 39
+	TotalPoints int
+
+	// TotalWords is the number of words in the solution.
+	// example: 5
+	TotalWords int
+
+	// RemainingPoints is the score for the words not yet found.
+	// example: 28
+	RemainingPoints int
+
+	// RemainingWords is the number of words not yet found.
+	// example: 3
+	RemainingWords int
+
+	// RemainingPangrams is the number of pangrams not yet found.
+	// example: 1
+	RemainingPangrams int
+
+	// RemainingGrid counts the words not yet found by first letter and length.
+	RemainingGrid []GridCount
+
+	// RemainingByLetter counts the words not yet found by first letter.
+	RemainingByLetter []LetterCount
+
+	// RemainingByLength counts the words not yet found by length.
+	RemainingByLength []LengthCount
+
+	// RemainingTwoLetters counts the words not yet found by their first two letters.
+	RemainingTwoLetters []PrefixCount
+
+	// Dictionary identifies the word lists used to answer the request.
+	Dictionary DictionaryVersion
+}
+
+// HintsRequest is the request object for SolverService.Hints
+type HintsRequest struct {
+	// Center is the center letter of the puzzle.
+	// example: "c"
+	Center string
+
+	// Hex is the other six letters of the puzzle.
+	// example: "hmnotu"
+	Hex string
+}
+
+// HintsResponse is the response object containing the hints for a puzzle.
+type HintsResponse struct {
+	// Words is the number of words in the solution.
+	// example: 5
+	Words int
+
+	// Points is the score for finding every word.
+	// example: 39
+	Points int
+
+	// Pangrams is the number of pangrams.
+	// example: 1
+	Pangrams int
+
+	// PerfectPangrams is the number of pangrams that use each letter once.
+	// example: 0
+	PerfectPangrams int
+
+	// Grid counts the words by first letter and length.
+	Grid []GridCount
+
+	// TwoLetters counts the words by their first two letters.
+	TwoLetters []PrefixCount
+
+	// Dictionary identifies the word lists used to answer the request.
+	Dictionary DictionaryVersion
+}
+
+// GridCount is the number of words with a first letter and length.
+type GridCount struct {
+	// Letter is the first letter of the words.
+	// example: "c"
+	Letter string
+
+	// Length is the length of the words.
+	// example: 6
+	Length int
+
+	// Count is the number of words.
+	// example: 1
+	Count int
+}
+
+// LetterCount is the number of words with a first letter.
+type LetterCount struct {
+	// Letter is the first letter of the words.
+	// example: "c"
+	Letter string
+
+	// Count is the number of words.
+	// example: 3
+	Count int
+}
+
+// LengthCount is the number of words with a length.
+type LengthCount struct {
+	// Length is the length of the words.
+	// example: 5
+	Length int
+
+	// Count is the number of words.
+	// example: 3
+	Count int
+}
+
+// PrefixCount is the number of words that start with a prefix.
+type PrefixCount struct {
+	// Prefix is the first letters of the words.
+	// example: "co"
+	Prefix string
+
+	// Count is the number of words.
+	// example: 3
+	Count int
+}
+
+// ParseFoundRequest is the request object for SolverService.ParseFound
+type ParseFoundRequest struct {
+	// Text is the text copied from the game's "You have found N words"
+	// panel or from a share message.
+	// example: "You have found 2 words\nCotton\nCount"
+	Text string
+
+	// Center letter is the required letter. It is optional; if it is empty,
+	// it is inferred from the found words.
+	// example: "c"
+	Center string
+
+	// Hex letters are the remaining six letters. They are optional; if they
+	// are empty, they are inferred from the found words.
+	// example: "hmnotu"
+	Hex string
+}
+
+// ParseFoundResponse is the response object containing the words read
+// from the text and the player's progress.
+type ParseFoundResponse struct {
+	// Center is the center letter that was given or inferred.
+	// example: "c"
+	Center string
+
+	// Hex is the hex letters that were given or inferred.
+	// example: "hmnotu"
+	Hex string
+
+	// Found is the list of words read from the text.
+	// example: ["cotton", "count"]
+	Found []string
+
+	// Claimed is the number of words the text says were found.
+	// It is zero if the text didn't say.
+	// example: 2
+	Claimed int
+
+	// Progress is the player's progress with the found words.
+	Progress ProgressResponse
+}
+
+// SearchRequest is the request object for WordSearchService.Search.
+// Every part that is set must match; at least one must be set.
+type SearchRequest struct {
+	// Prefix is the start of the word.
+	// example: "cot"
+	Prefix string
+
+	// Pattern is the whole word, where ? matches any one letter
+	// and * matches any run of letters, including none.
+	// example: "c?tt?n"
+	Pattern string
+
+	// Letters is a bag of letters to make the word from. Each letter may
+	// be used as many times as it appears, and ? is a blank that stands
+	// for any letter.
+	// example: "nottoc"
+	Letters string
+
+	// Exact requires the word to use every letter in Letters.
+	// example: true
+	Exact bool
+
+	// MinLength is the shortest word, in letters. Zero means no limit.
+	// example: 4
+	MinLength int
+
+	// MaxLength is the longest word, in letters. Zero means no limit.
+	// example: 8
+	MaxLength int
+
+	// Center and Hex restrict the words to a puzzle's letters.
+	// If either is set, both must be valid and words must use Center.
+	// example: "c"
+	Center string
+
+	// Hex is the puzzle's other six letters.
+	// example: "hmnotu"
+	Hex string
+
+	// Limit is the most words to return. Zero means no limit.
+	// example: 100
+	Limit int
+}
+
+// SearchResponse is the response object containing the matching words.
+type SearchResponse struct {
+	// Words is the list of matching words, sorted.
+	// example: ["cotton"]
+	Words []string
+
+	// Truncated is true if there were more matches than Limit.
+	// example: false
+	Truncated bool
+
+	// Dictionary identifies the word lists used to answer the request.
+	Dictionary DictionaryVersion
+}
+
+// DictionaryVersion identifies the word lists a server loaded.
+// Servers that give different answers for the same puzzle will have
+// different hashes.
+type DictionaryVersion struct {
+	// Hash is a hash of the contents of every loaded word list.
+	// example: "9f2c4e0b7a..."
+	Hash string
+
+	// LoadedAt is when the word lists were loaded, in RFC 3339 format.
+	// example: "2022-05-01T12:00:00Z"
+	LoadedAt string
+
+	// Words is the number of words in the dictionary.
+	// example: 370105
+	Words int
+}
+
+// VersionsRequest is the request object for SolverService.Versions.
+type VersionsRequest struct{}
+
+// VersionsResponse is the response object containing the dictionary versions.
+type VersionsResponse struct {
+	// Current is the version of the word lists in use.
+	Current DictionaryVersion
+
+	// Seen is the list of versions loaded since the server started, oldest first.
+	Seen []DictionaryVersion
 }
@@ -0,0 +1,196 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package hintspage reads the official Spelling Bee hints page from a
+// locally saved HTML file.
+package hintspage
+
+import (
+	"github.com/mdhender/queenie/internal/services/solver"
+	"github.com/pkg/errors"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Page is the information read from a hints page.
+type Page struct {
+	Center string // the center letter
+	Hex    string // the other six letters
+	Hints  *solver.Hints
+}
+
+var (
+	reComment  = regexp.MustCompile(`(?s)<!--.*?-->`)
+	reScript   = regexp.MustCompile(`(?is)<(script|style)\b.*?</(script|style)>`)
+	reCell     = regexp.MustCompile(`(?i)<(td|th)\b[^>]*>`)
+	reBlock    = regexp.MustCompile(`(?i)</?(p|div|tr|br|li|h[1-6]|table|tbody|thead|section|article|ul|ol)\b[^>]*>`)
+	reTag      = regexp.MustCompile(`<[^>]*>`)
+	reTotals   = regexp.MustCompile(`(?i)WORDS:\s*(\d+)\s*,\s*POINTS:\s*(\d+)\s*,\s*PANGRAMS:\s*(\d+)(?:\s*\(\s*(\d+)\s*Perfect\s*\))?`)
+	reTwoStart = regexp.MustCompile(`(?i)two[- ]letter list`)
+	reTwo      = regexp.MustCompile(`\b([A-Za-z]{2})-(\d+)\b`)
+)
+
+// Parse reads a saved hints page.
+// The page must include the letters, the totals, and the grid;
+// the two-letter list is read if it's present.
+func Parse(r io.Reader) (*Page, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	lines := toLines(string(b))
+
+	page := &Page{Hints: &solver.Hints{
+		Grid:       make(map[rune]map[int]int),
+		TwoLetters: make(map[string]int),
+	}}
+
+	// the letters are shown as seven single letters, center first
+	for _, line := range lines {
+		fields := strings.Fields(strings.ReplaceAll(line, "\t", " "))
+		if len(fields) != 7 {
+			continue
+		}
+		letters := ""
+		for _, field := range fields {
+			if r := []rune(field); len(r) == 1 && unicode.IsLetter(r[0]) {
+				letters += strings.ToLower(field)
+			}
+		}
+		if len([]rune(letters)) == 7 {
+			page.Center, page.Hex = letters[:1], letters[1:]
+			break
+		}
+	}
+	if page.Center == "" {
+		return nil, errors.New("hints page: letters not found")
+	}
+
+	text := strings.Join(lines, "\n")
+	m := reTotals.FindStringSubmatch(text)
+	if m == nil {
+		return nil, errors.New("hints page: word, point, and pangram totals not found")
+	}
+	page.Hints.Words, _ = strconv.Atoi(m[1])
+	page.Hints.Points, _ = strconv.Atoi(m[2])
+	page.Hints.Pangrams, _ = strconv.Atoi(m[3])
+	if m[4] != "" {
+		page.Hints.PerfectPangrams, _ = strconv.Atoi(m[4])
+	}
+
+	if err := parseGrid(lines, page.Hints); err != nil {
+		return nil, err
+	}
+
+	if loc := reTwoStart.FindStringIndex(text); loc != nil {
+		for _, m := range reTwo.FindAllStringSubmatch(text[loc[1]:], -1) {
+			count, _ := strconv.Atoi(m[2])
+			page.Hints.TwoLetters[strings.ToLower(m[1])] = count
+		}
+	}
+
+	return page, nil
+}
+
+// parseGrid reads the table of counts by first letter and length.
+// The header row lists the lengths followed by Σ, and each letter row
+// starts with the letter and a colon. A dash means no words.
+func parseGrid(lines []string, h *solver.Hints) error {
+	var lengths []int
+	for _, line := range lines {
+		cells := toCells(line)
+		if len(cells) < 2 {
+			continue
+		}
+		if lengths == nil {
+			// look for the header row
+			if cells[len(cells)-1] != "Σ" {
+				continue
+			}
+			for _, cell := range cells[:len(cells)-1] {
+				n, err := strconv.Atoi(cell)
+				if err != nil {
+					lengths = nil
+					break
+				}
+				lengths = append(lengths, n)
+			}
+			continue
+		}
+		// the totals row is labeled Σ, which is a letter too
+		label := []rune(cells[0])
+		if len(label) != 2 || label[1] != ':' || !unicode.IsLetter(label[0]) || label[0] == 'Σ' {
+			continue
+		}
+		letter := unicode.ToLower(label[0])
+		if len(cells) < len(lengths)+1 {
+			return errors.Errorf("hints page: grid row %q: want %d counts", cells[0], len(lengths))
+		}
+		for i, n := range lengths {
+			if cells[i+1] == "-" {
+				continue
+			}
+			count, err := strconv.Atoi(cells[i+1])
+			if err != nil {
+				return errors.Errorf("hints page: grid row %q: %q is not a count", cells[0], cells[i+1])
+			}
+			if h.Grid[letter] == nil {
+				h.Grid[letter] = make(map[int]int)
+			}
+			h.Grid[letter][n] = count
+		}
+	}
+	if lengths == nil {
+		return errors.New("hints page: grid not found")
+	}
+	return nil
+}
+
+// toLines converts the HTML to lines of text.
+// Table cells are separated by tabs so that rows can be split into cells.
+func toLines(s string) []string {
+	s = reComment.ReplaceAllString(s, "")
+	s = reScript.ReplaceAllString(s, "")
+	s = reCell.ReplaceAllString(s, "\t")
+	s = reBlock.ReplaceAllString(s, "\n")
+	s = reTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// toCells splits a line of text into non-empty table cells.
+func toCells(line string) []string {
+	var cells []string
+	for _, cell := range strings.Split(line, "\t") {
+		if cell = strings.TrimSpace(cell); cell != "" {
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
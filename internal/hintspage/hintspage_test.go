@@ -0,0 +1,110 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package hintspage
+
+import (
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	fp, err := os.Open("testdata/hints.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fp.Close()
+	page, err := Parse(fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if page.Center != "c" || page.Hex != "hmnotu" {
+		t.Errorf("letters: got %q %q, want %q %q", page.Center, page.Hex, "c", "hmnotu")
+	}
+
+	// the totals in the script and the comment must be ignored
+	h := page.Hints
+	if h.Words != 17 || h.Points != 71 || h.Pangrams != 1 || h.PerfectPangrams != 1 {
+		t.Errorf("totals: got %d words, %d points, %d pangrams (%d perfect), want 17, 71, 1 (1)",
+			h.Words, h.Points, h.Pangrams, h.PerfectPangrams)
+	}
+
+	grid := map[rune]map[int]int{
+		'c': {4: 3, 5: 2, 7: 1},
+		'h': {4: 1, 6: 2},
+		'm': {4: 2, 5: 1, 6: 1},
+		'n': {5: 1},
+		'o': {4: 1, 5: 1, 7: 1},
+	}
+	if !reflect.DeepEqual(h.Grid, grid) {
+		t.Errorf("grid: got %v, want %v", h.Grid, grid)
+	}
+
+	twoLetters := map[string]int{
+		"ch": 2, "co": 3, "cu": 1,
+		"ho": 2, "hu": 1,
+		"mo": 3, "mu": 1,
+		"no": 1,
+		"om": 1, "ou": 2,
+	}
+	if !reflect.DeepEqual(h.TwoLetters, twoLetters) {
+		t.Errorf("two letters: got %v, want %v", h.TwoLetters, twoLetters)
+	}
+}
+
+func TestParseWithoutTwoLetterList(t *testing.T) {
+	b, err := os.ReadFile("testdata/hints.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := regexp.MustCompile(`(?s)<h3>Two letter list:.*</article>`).ReplaceAllString(string(b), "</article>")
+	page, err := Parse(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Hints.TwoLetters) != 0 {
+		t.Errorf("two letters: got %v, want none", page.Hints.TwoLetters)
+	}
+	if page.Hints.Words != 17 || len(page.Hints.Grid) != 5 {
+		t.Errorf("got %d words and %d grid letters, want 17 and 5", page.Hints.Words, len(page.Hints.Grid))
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		html string
+		want string
+	}{
+		{"no letters", `<p>WORDS: 1, POINTS: 1, PANGRAMS: 0</p>`, "letters not found"},
+		{"no totals", `<p>C H M N O T U</p>`, "totals not found"},
+		{"no grid", `<p>C H M N O T U</p><p>WORDS: 1, POINTS: 1, PANGRAMS: 0</p>`, "grid not found"},
+		{"short row", `<p>C H M N O T U</p><p>WORDS: 1, POINTS: 1, PANGRAMS: 0</p>
+<table><tr><td>4</td><td>5</td><td>Σ</td></tr><tr><td>C:</td><td>1</td></tr></table>`, "want 2 counts"},
+	} {
+		_, err := Parse(strings.NewReader(tc.html))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: got error %v, want %q", tc.name, err, tc.want)
+		}
+	}
+}
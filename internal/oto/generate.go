@@ -0,0 +1,170 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package oto generates the service interfaces, handlers, and clients
+// from the definitions in internal/definition. It replaces the external
+// oto binary and renders the same templates in-process.
+package oto
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/doc"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+// Target is a template and the file it generates for each definition.
+type Target struct {
+	// Template is the name of an embedded template or the path to a template file.
+	Template string
+	// Output is the path of the generated file, relative to the root.
+	// Every "{pkg}" is replaced with the definition's package name.
+	Output string
+}
+
+// DefaultTargets are the files generated for every definition.
+var DefaultTargets = []Target{
+	{Template: "oto-server.go.tmpl", Output: "services/{pkg}/oto-service.go"},
+	{Template: "oto-client.go.tmpl", Output: "clients/{pkg}/oto-client.go"},
+	{Template: "oto-client.ts.tmpl", Output: "clients/{pkg}/oto-client.ts"},
+}
+
+// File is a generated file.
+type File struct {
+	Name     string // path relative to the root
+	Contents []byte
+}
+
+// Generate renders the targets for every definition in root/definition.
+// The files are returned rather than written so that callers can check
+// them against the files on disk.
+func Generate(root string, targets []Target) ([]File, error) {
+	definitions, err := filepath.Glob(filepath.Join(root, "definition", "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(definitions)
+
+	var templates []*template.Template
+	for _, target := range targets {
+		t, err := loadTemplate(target.Template)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+
+	var files []File
+	for _, dfn := range definitions {
+		def, err := Parse(dfn)
+		if err != nil {
+			return nil, err
+		}
+		for i, target := range targets {
+			var buf bytes.Buffer
+			if err := templates[i].Execute(&buf, def); err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", dfn, target.Template, err)
+			}
+			name := filepath.FromSlash(strings.ReplaceAll(target.Output, "{pkg}", def.PackageName))
+			contents := buf.Bytes()
+			if filepath.Ext(name) == ".go" {
+				if contents, err = format.Source(contents); err != nil {
+					return nil, fmt.Errorf("%s: %s: gofmt: %w", dfn, name, err)
+				}
+			}
+			files = append(files, File{Name: name, Contents: contents})
+		}
+	}
+	return files, nil
+}
+
+// Write saves the generated files under root.
+func Write(root string, files []File) error {
+	for _, f := range files {
+		name := filepath.Join(root, f.Name)
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			return err
+		} else if err := os.WriteFile(name, f.Contents, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stale returns the names of the generated files that are missing
+// or don't match the files under root.
+func Stale(root string, files []File) ([]string, error) {
+	var stale []string
+	for _, f := range files {
+		b, err := os.ReadFile(filepath.Join(root, f.Name))
+		if os.IsNotExist(err) {
+			stale = append(stale, f.Name)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(b, f.Contents) {
+			stale = append(stale, f.Name)
+		}
+	}
+	return stale, nil
+}
+
+// loadTemplate returns an embedded template or reads one from a file.
+func loadTemplate(name string) (*template.Template, error) {
+	b, err := templatesFS.ReadFile("templates/" + name)
+	if err != nil {
+		if b, err = os.ReadFile(name); err != nil {
+			return nil, fmt.Errorf("template: %w", err)
+		}
+	}
+	return template.New(filepath.Base(name)).Funcs(template.FuncMap{
+		"camelizeDown":        camelizeDown,
+		"formatComment":       func(s string) string { return formatComment(s, "") },
+		"formatCommentPrefix": formatComment,
+	}).Parse(string(b))
+}
+
+// formatComment wraps the text as a line comment. The template supplies
+// the prefix for the first line; formatComment adds it to the lines after
+// that, including the line that follows the comment.
+// It returns an empty string for empty text.
+func formatComment(s, prefix string) string {
+	if s == "" {
+		return ""
+	}
+	var buf bytes.Buffer
+	doc.ToText(&buf, s, "// ", "", 80)
+	var sb strings.Builder
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		sb.WriteString(line)
+		sb.WriteString("\n")
+		sb.WriteString(prefix)
+	}
+	return sb.String()
+}
@@ -0,0 +1,242 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package oto
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// Definition describes the services and objects in a single definition file.
+type Definition struct {
+	PackageName string
+	Services    []Service
+	Objects     []Object
+}
+
+// Service is an interface in the definition file.
+type Service struct {
+	Name    string
+	Comment string
+	Methods []Method
+}
+
+// Method is a method of a service.
+// Each method takes a single object and returns a single object.
+type Method struct {
+	Name         string
+	Comment      string
+	InputObject  FieldType
+	OutputObject FieldType
+}
+
+// Object is a struct in the definition file.
+type Object struct {
+	Name    string
+	Comment string
+	Fields  []Field
+}
+
+// Field is a field of an object.
+type Field struct {
+	Name           string
+	NameLowerCamel string
+	Comment        string
+	Type           FieldType
+	OmitEmpty      bool
+}
+
+// FieldType is the type of a field, input, or output.
+type FieldType struct {
+	TypeName string // the Go type, without the slice prefix
+	TSType   string // the TypeScript type, without the array suffix
+	Multiple bool   // true if the type is a slice
+}
+
+// Parse reads a definition file.
+// The package name for the generated code is taken from the file name,
+// so definition/solver.go generates package solver.
+func Parse(filename string) (*Definition, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	def := &Definition{PackageName: strings.TrimSuffix(filepath.Base(filename), ".go")}
+	outputs := make(map[string]bool)
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			doc := ts.Doc
+			if doc == nil {
+				doc = gd.Doc
+			}
+			switch t := ts.Type.(type) {
+			case *ast.InterfaceType:
+				service, err := parseService(fset, ts.Name.Name, doc, t)
+				if err != nil {
+					return nil, err
+				}
+				for _, method := range service.Methods {
+					outputs[method.OutputObject.TypeName] = true
+				}
+				def.Services = append(def.Services, service)
+			case *ast.StructType:
+				object, err := parseObject(fset, ts.Name.Name, doc, t)
+				if err != nil {
+					return nil, err
+				}
+				def.Objects = append(def.Objects, object)
+			default:
+				return nil, fmt.Errorf("%s: %s: want interface or struct", fset.Position(ts.Pos()), ts.Name.Name)
+			}
+		}
+	}
+
+	// every output object reports errors to the client
+	for i, object := range def.Objects {
+		if outputs[object.Name] {
+			def.Objects[i].Fields = append(def.Objects[i].Fields, Field{
+				Name:           "Error",
+				NameLowerCamel: "error",
+				Comment:        "Error is string explaining what went wrong. Empty if everything was fine.",
+				Type:           FieldType{TypeName: "string", TSType: "string"},
+				OmitEmpty:      true,
+			})
+		}
+	}
+
+	return def, nil
+}
+
+func parseService(fset *token.FileSet, name string, doc *ast.CommentGroup, t *ast.InterfaceType) (Service, error) {
+	service := Service{Name: name, Comment: commentText(doc)}
+	for _, m := range t.Methods.List {
+		ft, ok := m.Type.(*ast.FuncType)
+		if !ok || len(m.Names) != 1 {
+			return Service{}, fmt.Errorf("%s: %s: want methods only", fset.Position(m.Pos()), name)
+		}
+		if ft.Params.NumFields() != 1 || ft.Results.NumFields() != 1 {
+			return Service{}, fmt.Errorf("%s: %s.%s: want one input and one output object", fset.Position(m.Pos()), name, m.Names[0].Name)
+		}
+		in, err := parseType(fset, ft.Params.List[0].Type)
+		if err != nil {
+			return Service{}, err
+		}
+		out, err := parseType(fset, ft.Results.List[0].Type)
+		if err != nil {
+			return Service{}, err
+		}
+		service.Methods = append(service.Methods, Method{
+			Name:         m.Names[0].Name,
+			Comment:      commentText(m.Doc),
+			InputObject:  in,
+			OutputObject: out,
+		})
+	}
+	return service, nil
+}
+
+func parseObject(fset *token.FileSet, name string, doc *ast.CommentGroup, t *ast.StructType) (Object, error) {
+	object := Object{Name: name, Comment: commentText(doc)}
+	for _, f := range t.Fields.List {
+		ft, err := parseType(fset, f.Type)
+		if err != nil {
+			return Object{}, err
+		}
+		for _, ident := range f.Names {
+			object.Fields = append(object.Fields, Field{
+				Name:           ident.Name,
+				NameLowerCamel: camelizeDown(ident.Name),
+				Comment:        commentText(f.Doc),
+				Type:           ft,
+			})
+		}
+	}
+	return object, nil
+}
+
+// parseType accepts identifiers and slices of identifiers.
+func parseType(fset *token.FileSet, expr ast.Expr) (FieldType, error) {
+	var ft FieldType
+	if at, ok := expr.(*ast.ArrayType); ok && at.Len == nil {
+		ft.Multiple = true
+		expr = at.Elt
+	}
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return FieldType{}, fmt.Errorf("%s: unsupported type", fset.Position(expr.Pos()))
+	}
+	ft.TypeName = ident.Name
+	switch ident.Name {
+	case "bool":
+		ft.TSType = "boolean"
+	case "string":
+		ft.TSType = "string"
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		ft.TSType = "number"
+	default:
+		ft.TSType = ident.Name
+	}
+	return ft, nil
+}
+
+// commentText returns the comment without any "example:" lines.
+// Examples document the definition; they aren't part of the generated code.
+func commentText(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	var lines []string
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "example:") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// camelizeDown lower-cases the leading capitals of a name,
+// so Center becomes center and ID becomes id.
+func camelizeDown(s string) string {
+	runes := []rune(s)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) {
+			break
+		}
+		// keep the capital that starts the next word (e.g., the P in URLPath)
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
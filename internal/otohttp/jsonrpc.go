@@ -0,0 +1,206 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package otohttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"net/http"
+)
+
+// JSON-RPC 2.0 error codes (see https://www.jsonrpc.org/specification#error_object).
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+)
+
+// rpcRequest is a single JSON-RPC 2.0 request or notification.
+// A request without an id is a notification and gets no response.
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+// rpcResponse is a single JSON-RPC 2.0 response.
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// rpcError is the error object returned in a JSON-RPC 2.0 response.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// serveJSONRPC handles a JSON-RPC 2.0 request or batch of requests.
+// Method names are "Service.Method" and are dispatched to the handlers
+// added with Register, so both transports share the same services.
+func (s *Server) serveJSONRPC(w http.ResponseWriter, r *http.Request) {
+	var raw json.RawMessage
+	if err := Decode(r, &raw); err != nil {
+		var requestError *RequestError
+		if errors.As(err, &requestError) && requestError.Status != http.StatusBadRequest {
+			// oversized bodies and bad content types aren't JSON-RPC problems
+			s.OnErr(w, r, err)
+			return
+		}
+		s.encodeRPC(w, r, rpcResponse{JSONRPC: "2.0", Error: &rpcError{Code: rpcParseError, Message: "parse error"}, ID: json.RawMessage("null")})
+		return
+	}
+	raw = bytes.TrimSpace(raw)
+
+	// a batch is an array of requests
+	if len(raw) != 0 && raw[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(raw, &batch); err != nil {
+			s.encodeRPC(w, r, rpcResponse{JSONRPC: "2.0", Error: &rpcError{Code: rpcParseError, Message: "parse error"}, ID: json.RawMessage("null")})
+			return
+		} else if len(batch) == 0 {
+			s.encodeRPC(w, r, rpcResponse{JSONRPC: "2.0", Error: &rpcError{Code: rpcInvalidRequest, Message: "invalid request"}, ID: json.RawMessage("null")})
+			return
+		}
+		var responses []rpcResponse
+		for _, msg := range batch {
+			if response, ok := s.callRPC(r, msg); ok {
+				responses = append(responses, response)
+			}
+		}
+		if len(responses) == 0 {
+			// the batch contained only notifications
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		s.encodeRPC(w, r, responses)
+		return
+	}
+
+	response, ok := s.callRPC(r, raw)
+	if !ok {
+		// notifications get no response
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	s.encodeRPC(w, r, response)
+}
+
+// callRPC runs a single JSON-RPC request against the registered handlers.
+// It returns false if the request was a notification.
+func (s *Server) callRPC(r *http.Request, msg json.RawMessage) (rpcResponse, bool) {
+	response := rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null")}
+
+	var request rpcRequest
+	if err := json.Unmarshal(msg, &request); err != nil || request.JSONRPC != "2.0" || request.Method == "" {
+		// we can't tell if it was a notification, so we always respond
+		if err == nil && len(request.ID) != 0 {
+			response.ID = request.ID
+		}
+		response.Error = &rpcError{Code: rpcInvalidRequest, Message: "invalid request"}
+		return response, true
+	}
+	isNotification := len(request.ID) == 0
+	if !isNotification {
+		response.ID = request.ID
+	}
+
+	h, ok := s.routes[s.Basepath+request.Method]
+	if !ok {
+		response.Error = &rpcError{Code: rpcMethodNotFound, Message: "method not found"}
+		return response, !isNotification
+	}
+
+	// oto methods take a single object as input
+	params := bytes.TrimSpace(request.Params)
+	if len(params) == 0 || bytes.Equal(params, []byte("null")) {
+		params = []byte("{}")
+	} else if params[0] != '{' {
+		response.Error = &rpcError{Code: rpcInvalidParams, Message: "invalid params: expected an object"}
+		return response, !isNotification
+	}
+
+	// replay the call through the oto handler so that both transports behave the same
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, s.Basepath+request.Method, bytes.NewReader(params))
+	if err != nil {
+		response.Error = &rpcError{Code: rpcInternalError, Message: err.Error()}
+		return response, !isNotification
+	}
+	for k, v := range r.Header {
+		req.Header[k] = v
+	}
+	req.Header.Del("Accept-Encoding")
+	req.Header.Del("Content-Length")
+	req.Header.Set("Content-Type", "application/json")
+	req, cancel := s.withDeadline(req, request.Method)
+	defer cancel()
+	rec := &rpcRecorder{header: make(http.Header), status: http.StatusOK}
+	h.ServeHTTP(rec, req)
+
+	var result struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(rec.body.Bytes(), &result); err != nil {
+		response.Error = &rpcError{Code: rpcInternalError, Message: "invalid response from handler"}
+	} else if rec.status != http.StatusOK || result.Error != "" {
+		code := rpcInternalError
+		if 400 <= rec.status && rec.status < 500 {
+			code = rpcInvalidParams
+		}
+		response.Error = &rpcError{Code: code, Message: result.Error}
+	} else {
+		response.Result = rec.body.Bytes()
+	}
+
+	return response, !isNotification
+}
+
+// encodeRPC writes a JSON-RPC response.
+// JSON-RPC reports errors in the body, so the status is always 200.
+func (s *Server) encodeRPC(w http.ResponseWriter, r *http.Request, v interface{}) {
+	if err := Encode(w, r, http.StatusOK, v); err != nil {
+		s.OnErr(w, r, err)
+	}
+}
+
+// rpcRecorder captures the response from an oto handler.
+type rpcRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *rpcRecorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *rpcRecorder) Write(b []byte) (int, error) {
+	return rec.body.Write(b)
+}
+
+func (rec *rpcRecorder) WriteHeader(status int) {
+	rec.status = status
+}
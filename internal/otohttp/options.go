@@ -45,7 +45,10 @@ package otohttp
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 )
 
 // Option allows us to pass in options when creating a new server.
@@ -87,6 +90,41 @@ func WithDebugCors(debug bool) Option {
 	}
 }
 
+// WithDisallowUnknownFields changes whether Decode rejects unknown fields.
+// Default: true
+func WithDisallowUnknownFields(disallow bool) Option {
+	return func(s *Server) (err error) {
+		s.decode.disallowUnknownFields = disallow
+		return nil
+	}
+}
+
+// WithMaxBodyBytes changes the largest request body that Decode will accept.
+// Larger bodies are rejected with a 413 status.
+// Default: 1mb
+func WithMaxBodyBytes(n int64) Option {
+	return func(s *Server) (err error) {
+		if n < 1 {
+			return fmt.Errorf("max body bytes must be positive")
+		}
+		s.decode.maxBodyBytes = n
+		return nil
+	}
+}
+
+// WithMethodTimeout sets the deadline for a single service method.
+// The method is given as "Service.Method", for example "SolverService.Solve".
+// Methods that run past their deadline fail with a 504 status.
+func WithMethodTimeout(method string, d time.Duration) Option {
+	return func(s *Server) (err error) {
+		if !strings.Contains(method, ".") {
+			return fmt.Errorf("method timeout: %q: want Service.Method", method)
+		}
+		s.timeouts[method] = d
+		return nil
+	}
+}
+
 // WithNotFound changes the default not found handler.
 func WithNotFound(h http.Handler) Option {
 	return func(s *Server) (err error) {
@@ -102,3 +140,41 @@ func WithOnErr(fn func(http.ResponseWriter, *http.Request, error)) Option {
 		return nil
 	}
 }
+
+// WithRejectTrailingData changes whether Decode rejects anything after the JSON object.
+// Default: true
+func WithRejectTrailingData(reject bool) Option {
+	return func(s *Server) (err error) {
+		s.decode.rejectTrailingData = reject
+		return nil
+	}
+}
+
+// WithRequireJSON changes whether Decode requires a Content-Type of application/json.
+// Requests with any other content type are rejected with a 415 status.
+// Default: false
+func WithRequireJSON(require bool) Option {
+	return func(s *Server) (err error) {
+		s.decode.requireJSON = require
+		return nil
+	}
+}
+
+// WithRPCPath changes the default JSON-RPC path from `/rpc`.
+// An empty path disables the JSON-RPC endpoint.
+func WithRPCPath(path string) Option {
+	return func(s *Server) (err error) {
+		s.RPCPath = path
+		return nil
+	}
+}
+
+// WithTimeout sets the default deadline for service methods.
+// Methods that run past their deadline fail with a 504 status.
+// Default: no deadline
+func WithTimeout(d time.Duration) Option {
+	return func(s *Server) (err error) {
+		s.timeout = d
+		return nil
+	}
+}
@@ -45,6 +45,7 @@
 package otohttp
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"encoding/json"
@@ -53,6 +54,7 @@ import (
 	"github.com/pkg/errors"
 	"io"
 	"log"
+	"mime"
 	"net"
 	"net/http"
 	"strings"
@@ -68,6 +70,11 @@ type Server struct {
 	// Default: /oto/
 	Basepath string
 
+	// RPCPath is the path for the JSON-RPC 2.0 endpoint.
+	// Set to an empty string to disable JSON-RPC.
+	// Default: /rpc
+	RPCPath string
+
 	// NotFound is the http.Handler to use when a resource is not found.
 	NotFound http.Handler
 
@@ -77,7 +84,10 @@ type Server struct {
 	debug struct {
 		cors bool
 	}
-	routes map[string]http.Handler
+	decode   decodeOptions
+	timeout  time.Duration            // default deadline for service methods
+	timeouts map[string]time.Duration // deadlines for individual service methods
+	routes   map[string]http.Handler
 }
 
 // NewServer makes a new Server.
@@ -90,11 +100,21 @@ func NewServer(cfg *config.Config, opts ...func(*Server) error) (*Server, error)
 			}{
 				Error: err.Error(),
 			}
-			if err := Encode(w, r, http.StatusInternalServerError, errObj); err != nil {
+			status := http.StatusInternalServerError
+			var requestError *RequestError
+			if errors.As(err, &requestError) {
+				status = requestError.Status
+			} else if errors.Is(err, context.DeadlineExceeded) {
+				status = http.StatusGatewayTimeout
+			}
+			if err := Encode(w, r, status, errObj); err != nil {
 				log.Printf("failed to encode error: %s\n", err)
 			}
 		},
 		NotFound: http.NotFoundHandler(),
+		RPCPath:  "/rpc",
+		decode:   defaultDecodeOptions(),
+		timeouts: make(map[string]time.Duration),
 		routes:   make(map[string]http.Handler),
 	}
 
@@ -139,14 +159,39 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		s.NotFound.ServeHTTP(w, r)
 		return
 	}
+	// let Decode know how strict this server is
+	r = r.WithContext(context.WithValue(r.Context(), decodeOptionsKey{}, s.decode))
+
+	if s.RPCPath != "" && r.URL.Path == s.RPCPath {
+		s.serveJSONRPC(w, r)
+		return
+	}
 	h, ok := s.routes[r.URL.Path]
 	if !ok {
 		s.NotFound.ServeHTTP(w, r)
 		return
 	}
+	r, cancel := s.withDeadline(r, strings.TrimPrefix(r.URL.Path, s.Basepath))
+	defer cancel()
 	h.ServeHTTP(w, r)
 }
 
+// withDeadline adds the deadline for the service method to the request context.
+// The method is given as "Service.Method".
+// Methods without a timeout still get a cancelable context.
+func (s *Server) withDeadline(r *http.Request, method string) (*http.Request, context.CancelFunc) {
+	timeout, ok := s.timeouts[method]
+	if !ok {
+		timeout = s.timeout
+	}
+	if timeout <= 0 {
+		ctx, cancel := context.WithCancel(r.Context())
+		return r.WithContext(ctx), cancel
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	return r.WithContext(ctx), cancel
+}
+
 // Encode writes the response.
 func Encode(w http.ResponseWriter, r *http.Request, status int, v interface{}) error {
 	b, err := json.Marshal(v)
@@ -169,14 +214,89 @@ func Encode(w http.ResponseWriter, r *http.Request, status int, v interface{}) e
 }
 
 // Decode unmarshals the object in the request into v.
+// The limits and strictness checks are taken from the options of the Server
+// that is handling the request. Failures are returned as a *RequestError
+// so that OnErr can report them with the right status.
 func Decode(r *http.Request, v interface{}) error {
-	bodyBytes, err := io.ReadAll(io.LimitReader(r.Body, 1024*1024))
+	opts, ok := r.Context().Value(decodeOptionsKey{}).(decodeOptions)
+	if !ok {
+		opts = defaultDecodeOptions()
+	}
+
+	if opts.requireJSON {
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || mediaType != "application/json" {
+			return &RequestError{Status: http.StatusUnsupportedMediaType, Message: "decode: Content-Type must be application/json"}
+		}
+	}
+
+	// read one byte past the limit so that we can tell when the body is too large
+	bodyBytes, err := io.ReadAll(io.LimitReader(r.Body, opts.maxBodyBytes+1))
 	if err != nil {
 		return fmt.Errorf("decode: read body: %w", err)
+	} else if int64(len(bodyBytes)) > opts.maxBodyBytes {
+		return &RequestError{Status: http.StatusRequestEntityTooLarge, Message: fmt.Sprintf("decode: body must not be larger than %d bytes", opts.maxBodyBytes)}
+	} else if len(bytes.TrimSpace(bodyBytes)) == 0 {
+		return &RequestError{Status: http.StatusBadRequest, Message: "decode: body must not be empty"}
 	}
-	err = json.Unmarshal(bodyBytes, v)
-	if err != nil {
-		return fmt.Errorf("decode: json.Unmarshal: %w", err)
+
+	dec := json.NewDecoder(bytes.NewReader(bodyBytes))
+	if opts.disallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(v); err != nil {
+		var syntaxError *json.SyntaxError
+		var typeError *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxError):
+			return &RequestError{Status: http.StatusBadRequest, Message: fmt.Sprintf("decode: malformed JSON at position %d", syntaxError.Offset)}
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			return &RequestError{Status: http.StatusBadRequest, Message: "decode: malformed JSON"}
+		case errors.As(err, &typeError):
+			return &RequestError{Status: http.StatusBadRequest, Message: fmt.Sprintf("decode: invalid value for %q at position %d", typeError.Field, typeError.Offset)}
+		case strings.HasPrefix(err.Error(), "json: unknown field "):
+			return &RequestError{Status: http.StatusBadRequest, Message: "decode: unknown field " + strings.TrimPrefix(err.Error(), "json: unknown field ")}
+		}
+		return &RequestError{Status: http.StatusBadRequest, Message: fmt.Sprintf("decode: %v", err)}
+	}
+	if opts.rejectTrailingData {
+		if err := dec.Decode(&struct{}{}); err != io.EOF {
+			return &RequestError{Status: http.StatusBadRequest, Message: "decode: body must contain a single JSON value"}
+		}
 	}
+
 	return nil
 }
+
+// RequestError is returned when the request can't be accepted.
+// Status is the HTTP status that should be returned to the client.
+type RequestError struct {
+	Status  int
+	Message string
+}
+
+func (e *RequestError) Error() string {
+	return e.Message
+}
+
+// decodeOptions controls how strictly Decode treats request bodies.
+// The server adds its options to the context of every request.
+type decodeOptions struct {
+	maxBodyBytes          int64
+	disallowUnknownFields bool
+	rejectTrailingData    bool
+	requireJSON           bool
+}
+
+type decodeOptionsKey struct{}
+
+// defaultDecodeOptions returns the strict defaults.
+// The Content-Type check is off by default since curl and friends
+// don't send it unless asked.
+func defaultDecodeOptions() decodeOptions {
+	return decodeOptions{
+		maxBodyBytes:          1024 * 1024,
+		disallowUnknownFields: true,
+		rejectTrailingData:    true,
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package archive
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ReadJSON reads puzzles from a history file in JSON.
+// The file is either a list of puzzles or an object with a "puzzles"
+// list, which is the format of the archive itself.
+func ReadJSON(r io.Reader) ([]ArchivedPuzzle, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var puzzles []ArchivedPuzzle
+	if trimmed := bytes.TrimSpace(b); len(trimmed) != 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(b, &puzzles)
+	} else {
+		var f archiveFile
+		err = json.Unmarshal(b, &f)
+		puzzles = f.Puzzles
+	}
+	if err != nil {
+		return nil, err
+	}
+	return puzzles, nil
+}
+
+// ReadCSV reads puzzles from a history file in CSV.
+// The first row names the columns: date, center, hex, answers, status,
+// and notes, in any order. Only date, center, and hex are required.
+// Answers are separated by spaces, commas, or semicolons.
+func ReadCSV(r io.Reader) ([]ArchivedPuzzle, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"date", "center", "hex"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing %q column", name)
+		}
+	}
+	column := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	var puzzles []ArchivedPuzzle
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		puzzles = append(puzzles, ArchivedPuzzle{
+			Date:   column(record, "date"),
+			Center: column(record, "center"),
+			Hex:    column(record, "hex"),
+			Answers: strings.FieldsFunc(column(record, "answers"), func(r rune) bool {
+				return r == ' ' || r == ',' || r == ';'
+			}),
+			Status: column(record, "status"),
+			Notes:  column(record, "notes"),
+		})
+	}
+	return puzzles, nil
+}
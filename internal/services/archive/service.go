@@ -0,0 +1,94 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package archive
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+type Service struct {
+	store *Store
+}
+
+// NewService returns a service for the puzzles in the store.
+func NewService(store *Store) Service {
+	return Service{store: store}
+}
+
+// List returns the archived puzzles that match the request, newest first.
+func (s Service) List(ctx context.Context, request ListRequest) (*ListResponse, error) {
+	word := strings.ToLower(strings.TrimSpace(request.Word))
+	letters := strings.ToLower(strings.TrimSpace(request.Letters))
+	status := strings.ToLower(strings.TrimSpace(request.Status))
+
+	response := &ListResponse{Puzzles: []ArchivedPuzzle{}}
+	for _, p := range s.store.List() {
+		if request.From != "" && p.Date < request.From {
+			continue
+		} else if request.To != "" && p.Date > request.To {
+			continue
+		} else if status != "" && p.Status != status {
+			continue
+		} else if letters != "" && !usesLetters(p, letters) {
+			continue
+		} else if word != "" && !hasAnswer(p, word) {
+			continue
+		}
+		response.Puzzles = append(response.Puzzles, p)
+		if request.Limit > 0 && len(response.Puzzles) == request.Limit {
+			break
+		}
+	}
+	return response, nil
+}
+
+// Get returns the archived puzzle for a date.
+func (s Service) Get(ctx context.Context, request GetRequest) (*GetResponse, error) {
+	if request.Date == "" {
+		return nil, fmt.Errorf("missing 'date'")
+	}
+	p, ok := s.store.Get(request.Date)
+	if !ok {
+		return nil, fmt.Errorf("no puzzle for %q", request.Date)
+	}
+	return &GetResponse{Puzzle: p}, nil
+}
+
+// usesLetters returns true if the puzzle uses every one of the letters.
+func usesLetters(p ArchivedPuzzle, letters string) bool {
+	for _, r := range letters {
+		if !strings.ContainsRune(p.Center+p.Hex, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasAnswer returns true if the word is one of the puzzle's answers.
+func hasAnswer(p ArchivedPuzzle, word string) bool {
+	for _, answer := range p.Answers {
+		if answer == word {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,220 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/mdhender/queenie/internal/services/solver"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Puzzle statuses.
+const (
+	StatusComplete = "complete" // the answers are the full official list
+	StatusPartial  = "partial"  // only some of the answers are known
+)
+
+// Store is an archive of past puzzles, kept in a JSON file.
+// It is safe for concurrent use.
+type Store struct {
+	path    string
+	mu      sync.RWMutex
+	puzzles map[string]ArchivedPuzzle // keyed by date
+}
+
+// archiveFile is the layout of the file on disk.
+type archiveFile struct {
+	Puzzles []ArchivedPuzzle `json:"puzzles"`
+}
+
+// Open loads the archive from a file.
+// A missing file is an empty archive; it is created on the first Put.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, puzzles: make(map[string]ArchivedPuzzle)}
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var f archiveFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, p := range f.Puzzles {
+		s.puzzles[p.Date] = p
+	}
+	return s, nil
+}
+
+// Get returns the puzzle for a date.
+func (s *Store) Get(date string) (ArchivedPuzzle, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.puzzles[date]
+	return p, ok
+}
+
+// List returns all the puzzles, newest first.
+func (s *Store) List() []ArchivedPuzzle {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	puzzles := make([]ArchivedPuzzle, 0, len(s.puzzles))
+	for _, p := range s.puzzles {
+		puzzles = append(puzzles, p)
+	}
+	sort.Slice(puzzles, func(i, j int) bool { return puzzles[i].Date > puzzles[j].Date })
+	return puzzles
+}
+
+// Put adds puzzles to the archive, replacing any with the same date, and
+// saves the file. Notes are kept from the old puzzle if the new one has none.
+// Nothing is changed unless every puzzle is valid.
+func (s *Store) Put(puzzles ...ArchivedPuzzle) (added, updated int, err error) {
+	var normalized []ArchivedPuzzle
+	for _, p := range puzzles {
+		if p, err = Normalize(p); err != nil {
+			return 0, 0, err
+		}
+		normalized = append(normalized, p)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous := make(map[string]ArchivedPuzzle)
+	for _, p := range normalized {
+		if old, ok := s.puzzles[p.Date]; ok {
+			if _, ok := previous[p.Date]; !ok {
+				previous[p.Date] = old
+			}
+			if p.Notes == "" {
+				p.Notes = old.Notes
+			}
+			updated++
+		} else {
+			added++
+		}
+		s.puzzles[p.Date] = p
+	}
+	if err := s.save(); err != nil {
+		// put back the puzzles we replaced so memory matches the file
+		for _, p := range normalized {
+			delete(s.puzzles, p.Date)
+		}
+		for date, p := range previous {
+			s.puzzles[date] = p
+		}
+		return 0, 0, err
+	}
+	return added, updated, nil
+}
+
+// save writes the archive to a temporary file and renames it over the
+// old one, so that a crash never leaves a partly written archive.
+// The caller must hold the lock.
+func (s *Store) save() error {
+	var f archiveFile
+	for _, p := range s.puzzles {
+		f.Puzzles = append(f.Puzzles, p)
+	}
+	sort.Slice(f.Puzzles, func(i, j int) bool { return f.Puzzles[i].Date < f.Puzzles[j].Date })
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	} else if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// Normalize checks a puzzle and returns it in the form it is stored:
+// lower-case letters with the hex letters sorted, and answers that are
+// lower-case, sorted, and unique. An empty status means complete if there
+// are answers and partial if there are none; a complete puzzle must have
+// answers. Every answer must satisfy the puzzle.
+func Normalize(p ArchivedPuzzle) (ArchivedPuzzle, error) {
+	p.Date = strings.TrimSpace(p.Date)
+	if _, err := time.Parse("2006-01-02", p.Date); err != nil {
+		return p, fmt.Errorf("date %q: must be YYYY-MM-DD", p.Date)
+	}
+	puzzle, err := solver.NewPuzzle(strings.TrimSpace(p.Center), strings.TrimSpace(p.Hex))
+	if err != nil {
+		return p, fmt.Errorf("%s: %w", p.Date, err)
+	}
+	p.Center = string(puzzle.Center)
+	hex := puzzle.Hex[:]
+	sort.Slice(hex, func(i, j int) bool { return hex[i] < hex[j] })
+	p.Hex = string(hex)
+
+	seen := make(map[string]bool)
+	var answers []string
+	for _, word := range p.Answers {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" || seen[word] {
+			continue
+		} else if !puzzle.Accepts(word) {
+			return p, fmt.Errorf("%s: answer %q doesn't satisfy the puzzle", p.Date, word)
+		}
+		seen[word] = true
+		answers = append(answers, word)
+	}
+	sort.Strings(answers)
+	p.Answers = answers
+
+	p.Status = strings.ToLower(strings.TrimSpace(p.Status))
+	switch p.Status {
+	case "":
+		p.Status = StatusComplete
+		if len(p.Answers) == 0 {
+			p.Status = StatusPartial
+		}
+	case StatusComplete:
+		if len(p.Answers) == 0 {
+			return p, fmt.Errorf("%s: status %q: no answers", p.Date, p.Status)
+		}
+	case StatusPartial:
+	default:
+		return p, fmt.Errorf("%s: status %q: must be %q or %q", p.Date, p.Status, StatusComplete, StatusPartial)
+	}
+	p.Notes = strings.TrimSpace(p.Notes)
+	return p, nil
+}
@@ -28,7 +28,6 @@ import (
 type Service struct{}
 
 func (s Service) Greet(ctx context.Context, request GreetRequest) (*GreetResponse, error) {
-	// consider rejecting unknown fields (https://www.alexedwards.net/blog/how-to-properly-parse-a-json-request-body)
 	if request.Name == "" {
 		return nil, errors.New("missing 'name'")
 	}
@@ -0,0 +1,92 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package solver
+
+import (
+	"math"
+	"strconv"
+)
+
+// Classifier estimates the chance that a word is accepted by the game.
+// It is a naive Bayes model over a word's letter pairs and triples,
+// length, and endings, trained on words known to be accepted or rejected.
+type Classifier struct {
+	counts [2]map[string]int // feature counts for accepted and rejected words
+	totals [2]int            // total feature counts
+	words  [2]int            // number of training words
+	vocab  map[string]bool   // every feature seen
+}
+
+// NewClassifier trains a classifier on accepted and rejected words.
+func NewClassifier(accepted, rejected map[string]bool) *Classifier {
+	c := &Classifier{
+		counts: [2]map[string]int{make(map[string]int), make(map[string]int)},
+		vocab:  make(map[string]bool),
+	}
+	for i, words := range []map[string]bool{accepted, rejected} {
+		for word := range words {
+			c.words[i]++
+			for _, f := range features(word) {
+				c.counts[i][f]++
+				c.totals[i]++
+				c.vocab[f] = true
+			}
+		}
+	}
+	return c
+}
+
+// Probability returns the chance that the word is accepted.
+// It is 0.5 if the classifier hasn't seen both kinds of word.
+func (c *Classifier) Probability(word string) float64 {
+	if c == nil || c.words[0] == 0 || c.words[1] == 0 {
+		return 0.5
+	}
+	var logs [2]float64
+	for i := range logs {
+		logs[i] = math.Log(float64(c.words[i]) / float64(c.words[0]+c.words[1]))
+		for _, f := range features(word) {
+			// add-one smoothing so that unseen features don't zero out a class
+			logs[i] += math.Log(float64(c.counts[i][f]+1) / float64(c.totals[i]+len(c.vocab)))
+		}
+	}
+	return 1 / (1 + math.Exp(logs[1]-logs[0]))
+}
+
+// features returns the features of a word. Letter pairs and triples are
+// padded with ^ and $ so that the start and end of the word count too.
+func features(word string) []string {
+	letters := []rune("^" + word + "$")
+	var f []string
+	for n := 2; n <= 3; n++ {
+		for i := 0; i+n <= len(letters); i++ {
+			f = append(f, string(letters[i:i+n]))
+		}
+	}
+	length := len(letters) - 2
+	if length > 12 {
+		length = 12
+	}
+	f = append(f, "len:"+strconv.Itoa(length))
+	for n := 2; n <= 4 && n < length; n++ {
+		f = append(f, "suffix:"+string(letters[len(letters)-1-n:len(letters)-1]))
+	}
+	return f
+}
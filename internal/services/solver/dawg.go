@@ -0,0 +1,255 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package solver
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// dawg is a directed acyclic word graph: a trie of the dictionary in
+// which identical subtrees are shared, so common endings like -ing and
+// -tion are stored once.
+type dawg struct {
+	once  sync.Once
+	words []string // the sorted words to build from
+	root  *dawgNode
+}
+
+type dawgNode struct {
+	id    int // set when the node is registered
+	final bool
+	edges []dawgEdge // sorted by letter
+}
+
+type dawgEdge struct {
+	r  rune
+	to *dawgNode
+}
+
+// newDawg returns a graph of the words, which must be sorted.
+// The graph is built the first time it is used.
+func newDawg(words []string) *dawg {
+	return &dawg{words: words}
+}
+
+// build constructs the minimal graph with Daciuk's incremental algorithm:
+// after each word is added, the part of the previous word that it
+// doesn't share is replaced by equal nodes that are already in the graph.
+func (d *dawg) build() {
+	b := &dawgBuilder{root: &dawgNode{}, register: make(map[string]*dawgNode)}
+	var prev []rune
+	for _, word := range d.words {
+		letters := []rune(word)
+		common := 0
+		for common < len(prev) && common < len(letters) && prev[common] == letters[common] {
+			common++
+		}
+		b.minimize(common)
+		node := b.root
+		if len(b.unchecked) != 0 {
+			node = b.unchecked[len(b.unchecked)-1].child
+		}
+		for _, r := range letters[common:] {
+			child := &dawgNode{}
+			node.edges = append(node.edges, dawgEdge{r: r, to: child})
+			b.unchecked = append(b.unchecked, dawgStep{parent: node, child: child})
+			node = child
+		}
+		node.final = true
+		prev = letters
+	}
+	b.minimize(0)
+	d.root = b.root
+	d.words = nil
+}
+
+type dawgBuilder struct {
+	root      *dawgNode
+	register  map[string]*dawgNode // registered nodes by signature
+	unchecked []dawgStep           // the path of the last word not yet minimized
+}
+
+type dawgStep struct {
+	parent, child *dawgNode
+}
+
+// minimize registers or replaces the unchecked nodes below depth.
+func (b *dawgBuilder) minimize(depth int) {
+	for i := len(b.unchecked) - 1; i >= depth; i-- {
+		step := b.unchecked[i]
+		key := signature(step.child)
+		if existing, ok := b.register[key]; ok {
+			step.parent.edges[len(step.parent.edges)-1].to = existing
+		} else {
+			step.child.id = len(b.register) + 1
+			b.register[key] = step.child
+		}
+	}
+	b.unchecked = b.unchecked[:depth]
+}
+
+// signature identifies a node by whether it ends a word and where its
+// edges lead. Its children must already be registered.
+func signature(n *dawgNode) string {
+	var sb strings.Builder
+	if n.final {
+		sb.WriteByte('!')
+	}
+	for _, e := range n.edges {
+		sb.WriteRune(e.r)
+		sb.WriteString(strconv.Itoa(e.to.id))
+		sb.WriteByte(',')
+	}
+	return sb.String()
+}
+
+// query is a parsed search request.
+type query struct {
+	prefix  []rune
+	pattern []rune // nil if there's no pattern; at most 63 runes
+	bag     map[rune]int
+	blanks  int
+	useBag  bool
+	exact   bool
+	allowed map[rune]bool // nil if any letter is allowed
+	center  rune
+	min     int
+	max     int
+	limit   int
+}
+
+// search returns the words that match the query, in order.
+// It returns true if it stopped at the query's limit.
+func (d *dawg) search(ctx context.Context, q *query) ([]string, bool, error) {
+	d.once.Do(d.build)
+	s := &searcher{ctx: ctx, q: q, words: []string{}}
+	s.walk(d.root, nil, s.closure(1), false)
+	return s.words, s.truncated, s.err
+}
+
+type searcher struct {
+	ctx       context.Context
+	q         *query
+	words     []string
+	truncated bool
+	visited   int
+	err       error
+}
+
+// walk visits the node reached by word. states is the set of pattern
+// positions that word can have reached, as a bit mask.
+func (s *searcher) walk(n *dawgNode, word []rune, states uint64, hasCenter bool) bool {
+	if s.visited++; s.visited%4096 == 0 {
+		if s.err = s.ctx.Err(); s.err != nil {
+			return false
+		}
+	}
+	q := s.q
+	depth := len(word)
+	if n.final && depth >= q.min && depth >= len(q.prefix) && (q.allowed == nil || hasCenter) &&
+		(q.pattern == nil || states&(1<<len(q.pattern)) != 0) && (!q.exact || s.bagEmpty()) {
+		if q.limit > 0 && len(s.words) == q.limit {
+			s.truncated = true
+			return false
+		}
+		s.words = append(s.words, string(word))
+	}
+	if q.max > 0 && depth >= q.max {
+		return true
+	}
+	for _, e := range n.edges {
+		if depth < len(q.prefix) && e.r != q.prefix[depth] {
+			continue
+		} else if q.allowed != nil && !q.allowed[e.r] {
+			continue
+		}
+		next := states
+		if q.pattern != nil {
+			if next = s.step(states, e.r); next == 0 {
+				continue
+			}
+		}
+		blank := false
+		if q.useBag {
+			if q.bag[e.r] > 0 {
+				q.bag[e.r]--
+			} else if q.blanks > 0 {
+				q.blanks--
+				blank = true
+			} else {
+				continue
+			}
+		}
+		ok := s.walk(e.to, append(word, e.r), next, hasCenter || e.r == q.center)
+		if q.useBag {
+			if blank {
+				q.blanks++
+			} else {
+				q.bag[e.r]++
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// step returns the pattern positions reachable by matching r from states.
+func (s *searcher) step(states uint64, r rune) uint64 {
+	var next uint64
+	for i, p := range s.q.pattern {
+		if states&(1<<i) == 0 {
+			continue
+		}
+		switch {
+		case p == '*':
+			next |= 1 << i
+		case p == '?' || p == r:
+			next |= 1 << (i + 1)
+		}
+	}
+	return s.closure(next)
+}
+
+// closure adds the positions after each * in states, since * can match nothing.
+func (s *searcher) closure(states uint64) uint64 {
+	for i, p := range s.q.pattern {
+		if p == '*' && states&(1<<i) != 0 {
+			states |= 1 << (i + 1)
+		}
+	}
+	return states
+}
+
+func (s *searcher) bagEmpty() bool {
+	if s.q.blanks != 0 {
+		return false
+	}
+	for _, n := range s.q.bag {
+		if n != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,165 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package solver
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// reFoundHeader matches the header of the game's found-words panel.
+var reFoundHeader = regexp.MustCompile(`(?i)you have found\s+(\d+)\s+words?`)
+
+// ParseFoundText pulls the found words out of text copied from the game's
+// "You have found N words" panel. It returns the words in the order they
+// appear, lower-cased and without duplicates, and the number of words the
+// panel claims (zero if the header wasn't found).
+//
+// Only lines that hold a single word, as they do in the panel, are used.
+// That keeps the rest of a share message from being mistaken for found
+// words; text with no such lines has no found words.
+//
+// The rank label sits above the header when the whole panel is copied,
+// so text before the header is ignored. Without a header, a rank name
+// on the first single-word line is taken to be the label and skipped.
+func ParseFoundText(text string) (words []string, claimed int) {
+	header := false
+	if loc := reFoundHeader.FindStringSubmatchIndex(text); loc != nil {
+		claimed, _ = strconv.Atoi(text[loc[2]:loc[3]])
+		text, header = text[loc[1]:], true
+	}
+
+	split := func(s string) []string {
+		return strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && r != '\'' })
+	}
+	var tokens []string
+	for _, line := range strings.Split(text, "\n") {
+		if fields := split(line); len(fields) == 1 {
+			if len(tokens) == 0 && !header && isRankName(fields[0]) {
+				continue
+			}
+			tokens = append(tokens, fields[0])
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, token := range tokens {
+		word := strings.ToLower(token)
+		if len([]rune(word)) < 4 || strings.ContainsRune(word, '\'') || seen[word] {
+			continue
+		}
+		seen[word] = true
+		words = append(words, word)
+	}
+	return words, claimed
+}
+
+// isRankName returns true if the word is the name of one of the game's ranks.
+func isRankName(word string) bool {
+	for _, r := range ranks {
+		if strings.EqualFold(r.name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// InferCenter works out the center letter from the found words when the
+// hex letters are known: it is the only letter that every word shares
+// and that isn't one of the hex letters.
+func InferCenter(words []string, hex string) (string, error) {
+	if len(words) == 0 {
+		return "", errors.New("no found words to infer the center letter from")
+	}
+	var common map[rune]bool
+	for _, word := range words {
+		letters := make(map[rune]bool)
+		for _, r := range word {
+			if !strings.ContainsRune(strings.ToLower(hex), r) && (common == nil || common[r]) {
+				letters[r] = true
+			}
+		}
+		common = letters
+	}
+	if len(common) != 1 {
+		return "", fmt.Errorf("found words share %d letters outside the hex; pass the center letter", len(common))
+	}
+	for r := range common {
+		return string(r), nil
+	}
+	return "", nil
+}
+
+// InferPuzzle works out the puzzle letters from the found words.
+// The center letter is the only letter every word shares, and the hex
+// letters are the rest of the letters used. If the center letter is
+// already known, pass it in; otherwise pass an empty string.
+// It fails when the words don't pin down all seven letters.
+func InferPuzzle(words []string, center string) (string, string, error) {
+	if len(words) == 0 {
+		return "", "", errors.New("no found words to infer the puzzle from")
+	}
+	used, common := make(map[rune]bool), make(map[rune]bool)
+	for i, word := range words {
+		letters := make(map[rune]bool)
+		for _, r := range word {
+			letters[r] = true
+			used[r] = true
+		}
+		if i == 0 {
+			common = letters
+			continue
+		}
+		for r := range common {
+			if !letters[r] {
+				delete(common, r)
+			}
+		}
+	}
+	if center != "" {
+		// the center letter is known, so only the hex letters need inferring
+		r := unicode.ToLower([]rune(center)[0])
+		used[r], common = true, map[rune]bool{r: true}
+	}
+	if len(used) > 7 {
+		return "", "", fmt.Errorf("found words use %d letters; a puzzle has 7", len(used))
+	} else if len(used) < 7 {
+		return "", "", fmt.Errorf("found words use only %d letters; pass the puzzle letters", len(used))
+	} else if len(common) != 1 {
+		return "", "", fmt.Errorf("found words share %d letters; pass the center letter", len(common))
+	}
+
+	var letters []rune
+	center = ""
+	for r := range used {
+		if common[r] {
+			center = string(r)
+		} else {
+			letters = append(letters, r)
+		}
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+	return center, string(letters), nil
+}
@@ -0,0 +1,131 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package solver
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFoundText(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		text    string
+		words   []string
+		claimed int
+	}{
+		{
+			name:    "panel",
+			text:    "You have found 3 words\nCotton\nCouch\nMouth\n",
+			words:   []string{"cotton", "couch", "mouth"},
+			claimed: 3,
+		},
+		{
+			name:    "panel with rank label",
+			text:    "Genius\nYou have found 4 words\nCotton\nCouch\nMouth\nNotch",
+			words:   []string{"cotton", "couch", "mouth", "notch"},
+			claimed: 4,
+		},
+		{
+			name:  "rank label without header",
+			text:  "Good\nCotton\r\nCouch\r\n",
+			words: []string{"cotton", "couch"},
+		},
+		{
+			// only a leading label is skipped; a found word can share a rank's name
+			name:  "rank name as a found word",
+			text:  "Cotton\nGood\n",
+			words: []string{"cotton", "good"},
+		},
+		{
+			name:    "share message",
+			text:    "I found 2 words in today's Spelling Bee!\nYou have found 2 words\nCotton\ncotton\nCount",
+			words:   []string{"cotton", "count"},
+			claimed: 2,
+		},
+		{
+			name:  "short words and contractions",
+			text:  "Cot\nDon't\nCount",
+			words: []string{"count"},
+		},
+		{
+			name: "prose",
+			text: "I found words in the spelling bee today",
+		},
+	} {
+		words, claimed := ParseFoundText(tc.text)
+		if !reflect.DeepEqual(words, tc.words) || claimed != tc.claimed {
+			t.Errorf("%s: got %q, %d, want %q, %d", tc.name, words, claimed, tc.words, tc.claimed)
+		}
+	}
+}
+
+func TestInferPuzzle(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		words       []string
+		center      string
+		wantCenter  string
+		wantHex     string
+		errContains string
+	}{
+		{name: "inferred", words: []string{"cotton", "couch", "much", "count"}, wantCenter: "c", wantHex: "hmnotu"},
+		{name: "center given", words: []string{"cotton", "mouth", "much", "count"}, center: "C", wantCenter: "c", wantHex: "hmnotu"},
+		{name: "center from the words", words: []string{"cotton", "couch", "mouth", "notch"}, wantCenter: "o", wantHex: "chmntu"},
+		{name: "too few letters", words: []string{"cotton", "count"}, errContains: "only 5 letters"},
+		{name: "two shared", words: []string{"cotton", "comu", "chon"}, errContains: "share 2 letters"},
+		{name: "too many letters", words: []string{"cotton", "pizza"}, errContains: "a puzzle has 7"},
+		{name: "no words", errContains: "no found words"},
+	} {
+		center, hex, err := InferPuzzle(tc.words, tc.center)
+		if tc.errContains != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.errContains) {
+				t.Errorf("%s: got error %v, want %q", tc.name, err, tc.errContains)
+			}
+		} else if err != nil || center != tc.wantCenter || hex != tc.wantHex {
+			t.Errorf("%s: got %q %q %v, want %q %q", tc.name, center, hex, err, tc.wantCenter, tc.wantHex)
+		}
+	}
+}
+
+func TestInferCenter(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		words       []string
+		hex         string
+		want        string
+		errContains string
+	}{
+		{name: "one letter left", words: []string{"cotton", "much"}, hex: "hmnotu", want: "c"},
+		{name: "upper-case hex", words: []string{"cotton", "much"}, hex: "HMNOTU", want: "c"},
+		{name: "not pinned down", words: []string{"cotton"}, hex: "hmnoux", errContains: "share 2 letters"},
+		{name: "no words", hex: "hmnotu", errContains: "no found words"},
+	} {
+		center, err := InferCenter(tc.words, tc.hex)
+		if tc.errContains != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.errContains) {
+				t.Errorf("%s: got error %v, want %q", tc.name, err, tc.errContains)
+			}
+		} else if err != nil || center != tc.want {
+			t.Errorf("%s: got %q %v, want %q", tc.name, center, err, tc.want)
+		}
+	}
+}
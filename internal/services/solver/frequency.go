@@ -0,0 +1,71 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package solver
+
+import (
+	"github.com/mdhender/queenie/internal/wordlist"
+	"sort"
+)
+
+// LoadFrequencies returns the frequency rank of each word in a file,
+// where 1 is the most common word, along with the load report.
+//
+// The file is read with wordlist.LoadFrequencies: each line is a word,
+// optionally followed by whitespace and a count. If the file has counts,
+// words are ranked by count, highest first; otherwise they are ranked in
+// the order they appear. The first line for a word wins.
+func LoadFrequencies(filename string) (map[string]int, *wordlist.Report, error) {
+	entries, report, err := wordlist.LoadFrequencies(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	counted := false
+	for _, e := range entries {
+		counted = counted || e.Frequency > 0
+	}
+	if counted {
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].Frequency > entries[j].Frequency })
+	}
+	ranks := make(map[string]int, len(entries))
+	for i, e := range entries {
+		ranks[e.Word] = i + 1
+	}
+	return ranks, report, nil
+}
+
+// rankEntries ranks the entries that have a frequency, highest first.
+// It returns nil if none of them do.
+func rankEntries(entries []wordlist.Entry) map[string]int {
+	var ranked []wordlist.Entry
+	for _, e := range entries {
+		if e.Frequency > 0 {
+			ranked = append(ranked, e)
+		}
+	}
+	if len(ranked) == 0 {
+		return nil
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Frequency > ranked[j].Frequency })
+	ranks := make(map[string]int)
+	for i, e := range ranked {
+		ranks[e.Word] = i + 1
+	}
+	return ranks
+}
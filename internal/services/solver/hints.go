@@ -0,0 +1,171 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package solver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Hints summarizes a list of words the way the official hints page does,
+// without giving away the words themselves.
+type Hints struct {
+	Words           int
+	Points          int
+	Pangrams        int
+	PerfectPangrams int // pangrams that use each letter exactly once
+	// Grid counts words by first letter and then by length.
+	Grid map[rune]map[int]int
+	// TwoLetters counts words by their first two letters.
+	TwoLetters map[string]int
+}
+
+// NewHints returns the hints for the words in the puzzle.
+// Words that the puzzle doesn't accept are ignored.
+func NewHints(p Puzzle, words []string) *Hints {
+	h := &Hints{
+		Grid:       make(map[rune]map[int]int),
+		TwoLetters: make(map[string]int),
+	}
+	for _, word := range words {
+		letters := []rune(word)
+		if len(letters) < 4 || !p.Accepts(word) {
+			continue
+		}
+		h.Words++
+		h.Points += p.Score(word)
+		if p.IsPangram(word) {
+			h.Pangrams++
+			if len(letters) == 7 {
+				h.PerfectPangrams++
+			}
+		}
+		if h.Grid[letters[0]] == nil {
+			h.Grid[letters[0]] = make(map[int]int)
+		}
+		h.Grid[letters[0]][len(letters)]++
+		h.TwoLetters[string(letters[:2])]++
+	}
+	return h
+}
+
+// Letters returns the first letters in the grid, sorted.
+func (h *Hints) Letters() []rune {
+	var letters []rune
+	for r := range h.Grid {
+		letters = append(letters, r)
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+	return letters
+}
+
+// Lengths returns the word lengths in the grid, sorted.
+func (h *Hints) Lengths() []int {
+	seen := make(map[int]bool)
+	var lengths []int
+	for _, counts := range h.Grid {
+		for n := range counts {
+			if !seen[n] {
+				seen[n] = true
+				lengths = append(lengths, n)
+			}
+		}
+	}
+	sort.Ints(lengths)
+	return lengths
+}
+
+// HintDiff is a count that differs between two sets of hints.
+type HintDiff struct {
+	What     string // e.g. "words", "C5", "C total", or "CO"
+	Expected int
+	Actual   int
+}
+
+// Diff compares the hints to the expected hints, such as those from the
+// official hints page, and returns the counts that disagree.
+// Totals come first, then the grid, then the two-letter list.
+// The two-letter list is only compared when the expected hints have one,
+// since a saved hints page may leave it out.
+func (h *Hints) Diff(expected *Hints) []HintDiff {
+	var diffs []HintDiff
+	add := func(what string, expected, actual int) {
+		if expected != actual {
+			diffs = append(diffs, HintDiff{What: what, Expected: expected, Actual: actual})
+		}
+	}
+	add("words", expected.Words, h.Words)
+	add("points", expected.Points, h.Points)
+	add("pangrams", expected.Pangrams, h.Pangrams)
+	add("perfect pangrams", expected.PerfectPangrams, h.PerfectPangrams)
+
+	// merge the letters and lengths so that cells missing from either side are reported
+	letters, lengths := make(map[rune]bool), make(map[int]bool)
+	for _, hints := range []*Hints{expected, h} {
+		for _, r := range hints.Letters() {
+			letters[r] = true
+		}
+		for _, n := range hints.Lengths() {
+			lengths[n] = true
+		}
+	}
+	var sortedLetters []rune
+	for r := range letters {
+		sortedLetters = append(sortedLetters, r)
+	}
+	sort.Slice(sortedLetters, func(i, j int) bool { return sortedLetters[i] < sortedLetters[j] })
+	var sortedLengths []int
+	for n := range lengths {
+		sortedLengths = append(sortedLengths, n)
+	}
+	sort.Ints(sortedLengths)
+	for _, r := range sortedLetters {
+		expectedSum, actualSum := 0, 0
+		for _, n := range sortedLengths {
+			add(fmt.Sprintf("%c%d", unicode.ToUpper(r), n), expected.Grid[r][n], h.Grid[r][n])
+			expectedSum += expected.Grid[r][n]
+			actualSum += h.Grid[r][n]
+		}
+		add(fmt.Sprintf("%c total", unicode.ToUpper(r)), expectedSum, actualSum)
+	}
+
+	if len(expected.TwoLetters) == 0 {
+		return diffs
+	}
+	prefixes := make(map[string]bool)
+	for prefix := range expected.TwoLetters {
+		prefixes[prefix] = true
+	}
+	for prefix := range h.TwoLetters {
+		prefixes[prefix] = true
+	}
+	var sortedPrefixes []string
+	for prefix := range prefixes {
+		sortedPrefixes = append(sortedPrefixes, prefix)
+	}
+	sort.Strings(sortedPrefixes)
+	for _, prefix := range sortedPrefixes {
+		add(strings.ToUpper(prefix), expected.TwoLetters[prefix], h.TwoLetters[prefix])
+	}
+
+	return diffs
+}
@@ -0,0 +1,53 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package solver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHintsDiff(t *testing.T) {
+	p, err := NewPuzzle("c", "hmnotu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := NewHints(p, []string{"cotton", "count", "mutch", "touch"})
+
+	// the expected page is missing "touch", and has no two-letter list
+	expected := NewHints(p, []string{"cotton", "count", "mutch"})
+	expected.TwoLetters = map[string]int{}
+	want := []HintDiff{
+		{What: "words", Expected: 3, Actual: 4},
+		{What: "points", Expected: actual.Points - 5, Actual: actual.Points},
+		{What: "T5", Expected: 0, Actual: 1},
+		{What: "T total", Expected: 0, Actual: 1},
+	}
+	if got := actual.Diff(expected); !reflect.DeepEqual(got, want) {
+		t.Errorf("without two-letter list: got %v, want %v", got, want)
+	}
+
+	// with a two-letter list, the missing prefix is reported too
+	expected = NewHints(p, []string{"cotton", "count", "mutch"})
+	want = append(want, HintDiff{What: "TO", Expected: 0, Actual: 1})
+	if got := actual.Diff(expected); !reflect.DeepEqual(got, want) {
+		t.Errorf("with two-letter list: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,471 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package solver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Likelihood is the chance that a word is in the official answer list.
+type Likelihood struct {
+	Word        string
+	Probability float64
+	Known       string // "valid" or "invalid" if the word lists decide it, otherwise empty
+}
+
+// maxSolutions limits how many ways of filling one letter's grid row are
+// enumerated before falling back to treating each cell on its own.
+const maxSolutions = 250000
+
+// Infer works out which candidate words are probably in the official
+// answer list, given the official hints for the puzzle.
+//
+// Words in the valid list are accepted and words in the invalid list are
+// rejected. The remaining words are chosen to match the official grid,
+// two-letter list, and pangram count; each word's probability is the
+// share of the consistent choices that include it, weighted by the
+// classifier's estimate for the word.
+//
+// It also returns the conflicts found, such as cells that need more words
+// than the dictionary has. Cells with conflicts are estimated on their own.
+func (s Service) Infer(ctx context.Context, puzzle Puzzle, official *Hints) ([]Likelihood, []string, error) {
+	words, err := s.scan(ctx, puzzle)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var results []Likelihood
+	var unknown []string
+	var knownWords []string
+	for _, word := range words {
+		switch {
+		case s.invalid[word]:
+			results = append(results, Likelihood{Word: word, Probability: 0, Known: "invalid"})
+		case s.valid[word]:
+			results = append(results, Likelihood{Word: word, Probability: 1, Known: "valid"})
+			knownWords = append(knownWords, word)
+		default:
+			unknown = append(unknown, word)
+		}
+	}
+	known := NewHints(puzzle, knownWords)
+
+	var conflicts []string
+	// points are fixed by the grid and the pangram count, so they can only be checked
+	expectedPoints := 7 * official.Pangrams
+	for _, counts := range official.Grid {
+		for n, count := range counts {
+			if n == 4 {
+				expectedPoints += count
+			} else {
+				expectedPoints += n * count
+			}
+		}
+	}
+	if expectedPoints != official.Points {
+		conflicts = append(conflicts, fmt.Sprintf("points: the grid and pangrams add up to %d, not %d", expectedPoints, official.Points))
+	}
+
+	// group the unknown words into classes of interchangeable words
+	byLetter := make(map[rune]*inferLetter)
+	for _, word := range unknown {
+		letters := []rune(word)
+		key := inferKey{length: len(letters), prefix: string(letters[:2]), pangram: puzzle.IsPangram(word)}
+		il := byLetter[letters[0]]
+		if il == nil {
+			il = &inferLetter{letter: letters[0], classes: make(map[inferKey]*inferClass)}
+			byLetter[letters[0]] = il
+		}
+		c := il.classes[key]
+		if c == nil {
+			c = &inferClass{key: key}
+			il.classes[key] = c
+		}
+		c.words = append(c.words, word)
+		c.odds = append(c.odds, odds(s.classifier.Probability(word)))
+	}
+	for letter := range official.Grid {
+		if byLetter[letter] == nil {
+			byLetter[letter] = &inferLetter{letter: letter, classes: make(map[inferKey]*inferClass)}
+		}
+	}
+
+	// fill in each letter's row of the grid
+	var letters []*inferLetter
+	for _, il := range byLetter {
+		letters = append(letters, il)
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i].letter < letters[j].letter })
+	for _, il := range letters {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		conflicts = append(conflicts, il.solve(official, known)...)
+	}
+
+	// combine the letters so that the pangram count matches
+	needPangrams := official.Pangrams - known.Pangrams
+	total := 0.0
+	for _, il := range letters {
+		if !il.exact {
+			continue
+		}
+		others := []float64{1}
+		for _, other := range letters {
+			if other != il && other.exact {
+				others = convolve(others, other.byPangrams)
+			}
+		}
+		il.others = others
+	}
+	all := []float64{1}
+	for _, il := range letters {
+		if il.exact {
+			all = convolve(all, il.byPangrams)
+		}
+	}
+	if 0 <= needPangrams && needPangrams < len(all) {
+		total = all[needPangrams]
+	}
+	if total == 0 {
+		conflicts = append(conflicts, fmt.Sprintf("pangrams: can't match the official count of %d", official.Pangrams))
+		needPangrams = -1 // don't constrain the pangram count
+	}
+
+	for _, il := range letters {
+		results = append(results, il.likelihoods(needPangrams)...)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Probability != results[j].Probability {
+			return results[i].Probability > results[j].Probability
+		}
+		return results[i].Word < results[j].Word
+	})
+	return results, conflicts, nil
+}
+
+// inferKey identifies words that the official hints can't tell apart.
+type inferKey struct {
+	length  int
+	prefix  string
+	pangram bool
+}
+
+// inferClass is a set of interchangeable words.
+type inferClass struct {
+	key   inferKey
+	words []string
+	odds  []float64 // the prior odds of each word being accepted
+	// weight[x][p] is the weight of the solutions that choose x words
+	// from this class and have p pangrams in the letter's row
+	weight map[int]map[int]float64
+	// estimate is the probability of each word when the cell is estimated on its own
+	estimate []float64
+}
+
+// inferLetter is the unknown words that start with a letter.
+type inferLetter struct {
+	letter  rune
+	classes map[inferKey]*inferClass
+	exact   bool // true if the solutions were enumerated
+	ordered []*inferClass
+	// byPangrams[p] is the weight of the solutions with p pangrams
+	byPangrams []float64
+	// others is the same for all the other exact letters combined
+	others []float64
+}
+
+// solve enumerates the ways to choose words from the classes that match the
+// official counts for the letter. It returns any conflicts; if there are
+// any, each cell is estimated on its own instead.
+func (il *inferLetter) solve(official, known *Hints) (conflicts []string) {
+	upper := unicode.ToUpper(il.letter)
+
+	// how many more words each cell and two-letter start needs
+	needCell := make(map[int]int)
+	available := make(map[int]int)
+	for n, count := range official.Grid[il.letter] {
+		needCell[n] = count - known.Grid[il.letter][n]
+	}
+	for _, c := range il.classes {
+		available[c.key.length] += len(c.words)
+		if _, ok := needCell[c.key.length]; !ok {
+			needCell[c.key.length] = -known.Grid[il.letter][c.key.length]
+		}
+	}
+	for n, need := range needCell {
+		if need < 0 {
+			conflicts = append(conflicts, fmt.Sprintf("%c%d: the valid list has %d words, the official count is %d", upper, n, known.Grid[il.letter][n], official.Grid[il.letter][n]))
+		} else if need > available[n] {
+			conflicts = append(conflicts, fmt.Sprintf("%c%d: the official count is %d, the dictionary has only %d candidates", upper, n, official.Grid[il.letter][n], known.Grid[il.letter][n]+available[n]))
+		}
+	}
+	usePrefixes := len(official.TwoLetters) != 0
+	needPrefix := make(map[string]int)
+	if usePrefixes {
+		for prefix, count := range official.TwoLetters {
+			if []rune(prefix)[0] == il.letter {
+				needPrefix[prefix] = count - known.TwoLetters[prefix]
+			}
+		}
+		for _, c := range il.classes {
+			if _, ok := needPrefix[c.key.prefix]; !ok {
+				needPrefix[c.key.prefix] = -known.TwoLetters[c.key.prefix]
+			}
+		}
+		for prefix, need := range needPrefix {
+			if need < 0 {
+				conflicts = append(conflicts, fmt.Sprintf("%s: the valid list has %d words, the official count is %d", strings.ToUpper(prefix), known.TwoLetters[prefix], official.TwoLetters[prefix]))
+			}
+		}
+	}
+
+	for _, c := range il.classes {
+		c.weight = make(map[int]map[int]float64)
+		il.ordered = append(il.ordered, c)
+	}
+	// order the classes by cell so that each cell's sum can be checked as soon as it's complete
+	sort.Slice(il.ordered, func(i, j int) bool {
+		a, b := il.ordered[i].key, il.ordered[j].key
+		if a.length != b.length {
+			return a.length < b.length
+		} else if a.prefix != b.prefix {
+			return a.prefix < b.prefix
+		}
+		return !a.pangram && b.pangram
+	})
+
+	if len(conflicts) == 0 {
+		solutions, complete := il.enumerate(needCell, needPrefix, usePrefixes)
+		if !complete {
+			conflicts = append(conflicts, fmt.Sprintf("%c: too many combinations to check; estimating each cell on its own", upper))
+		} else if solutions == 0 {
+			conflicts = append(conflicts, fmt.Sprintf("%c: no choice of words matches the official counts; estimating each cell on its own", upper))
+		}
+		il.exact = complete && solutions != 0
+	}
+	if !il.exact {
+		// each cell on its own: choose as many of the cell's words as it needs, if possible
+		cellOdds := make(map[int][]float64)
+		for _, c := range il.ordered {
+			cellOdds[c.key.length] = append(cellOdds[c.key.length], c.odds...)
+		}
+		offset := make(map[int]int)
+		for _, c := range il.ordered {
+			n := c.key.length
+			need := needCell[n]
+			if need < 0 {
+				need = 0
+			} else if need > available[n] {
+				need = available[n]
+			}
+			c.estimate = nil
+			for i := range c.words {
+				c.estimate = append(c.estimate, chosen(cellOdds[n], offset[n]+i, need))
+			}
+			offset[n] += len(c.words)
+		}
+	}
+	return conflicts
+}
+
+// enumerate finds every way to choose counts from the classes that match
+// the cell and two-letter counts, and returns the number of solutions.
+// complete is false if there were more than maxSolutions to check.
+func (il *inferLetter) enumerate(needCell map[int]int, needPrefix map[string]int, usePrefixes bool) (solutions int, complete bool) {
+	counts := make([]int, len(il.ordered))
+	sums := make(map[int]int)
+	prefixSums := make(map[string]int)
+	var walk func(i int) bool
+	walk = func(i int) bool {
+		if i == len(il.ordered) {
+			if usePrefixes {
+				for prefix, need := range needPrefix {
+					if prefixSums[prefix] != need {
+						return true
+					}
+				}
+			}
+			for n, need := range needCell {
+				if sums[n] != need {
+					return true
+				}
+			}
+			solutions++
+			if solutions > maxSolutions {
+				return false
+			}
+			weight, pangrams := 1.0, 0
+			for j, c := range il.ordered {
+				weight *= elementary(c.odds, counts[j])
+				if c.key.pangram {
+					pangrams += counts[j]
+				}
+			}
+			for len(il.byPangrams) <= pangrams {
+				il.byPangrams = append(il.byPangrams, 0)
+			}
+			il.byPangrams[pangrams] += weight
+			for j, c := range il.ordered {
+				if c.weight[counts[j]] == nil {
+					c.weight[counts[j]] = make(map[int]float64)
+				}
+				c.weight[counts[j]][pangrams] += weight
+			}
+			return true
+		}
+		c := il.ordered[i]
+		for x := 0; x <= len(c.words); x++ {
+			if sums[c.key.length]+x > needCell[c.key.length] {
+				break
+			} else if usePrefixes && prefixSums[c.key.prefix]+x > needPrefix[c.key.prefix] {
+				break
+			}
+			counts[i] = x
+			sums[c.key.length] += x
+			prefixSums[c.key.prefix] += x
+			ok := true
+			// once the last class in a cell is chosen, the cell must be full
+			if i+1 == len(il.ordered) || il.ordered[i+1].key.length != c.key.length {
+				ok = sums[c.key.length] == needCell[c.key.length]
+			}
+			if ok && !walk(i+1) {
+				return false
+			}
+			sums[c.key.length] -= x
+			prefixSums[c.key.prefix] -= x
+		}
+		counts[i] = 0
+		return true
+	}
+	if !walk(0) {
+		for _, c := range il.ordered {
+			c.weight = make(map[int]map[int]float64)
+		}
+		il.byPangrams = nil
+		return solutions, false
+	}
+	return solutions, true
+}
+
+// likelihoods returns the probability for each word in the letter.
+// needPangrams is the number of pangrams the letters must supply, or -1
+// if the pangram count isn't constrained.
+func (il *inferLetter) likelihoods(needPangrams int) []Likelihood {
+	var results []Likelihood
+	for _, c := range il.ordered {
+		if !il.exact {
+			for i, word := range c.words {
+				results = append(results, Likelihood{Word: word, Probability: c.estimate[i]})
+			}
+			continue
+		}
+		// the weight of each count of chosen words, across all consistent solutions
+		byCount := make(map[int]float64)
+		for x, byP := range c.weight {
+			for p, w := range byP {
+				if needPangrams < 0 {
+					byCount[x] += w * sum(il.others)
+				} else if k := needPangrams - p; 0 <= k && k < len(il.others) {
+					byCount[x] += w * il.others[k]
+				}
+			}
+		}
+		total := 0.0
+		for _, w := range byCount {
+			total += w
+		}
+		for i, word := range c.words {
+			probability := 0.0
+			if total > 0 {
+				for x, w := range byCount {
+					probability += w / total * chosen(c.odds, i, x)
+				}
+			}
+			results = append(results, Likelihood{Word: word, Probability: probability})
+		}
+	}
+	return results
+}
+
+// chosen returns the chance that word i is one of the x words chosen,
+// when each set of x words is weighted by the product of their odds.
+func chosen(odds []float64, i, x int) float64 {
+	if x == 0 {
+		return 0
+	} else if x == len(odds) {
+		return 1
+	}
+	others := make([]float64, 0, len(odds)-1)
+	others = append(others, odds[:i]...)
+	others = append(others, odds[i+1:]...)
+	return odds[i] * elementary(others, x-1) / elementary(odds, x)
+}
+
+// elementary returns the elementary symmetric polynomial of degree k:
+// the sum, over every set of k values, of the product of the values.
+func elementary(values []float64, k int) float64 {
+	e := make([]float64, k+1)
+	e[0] = 1
+	for _, v := range values {
+		for j := k; j > 0; j-- {
+			e[j] += e[j-1] * v
+		}
+	}
+	return e[k]
+}
+
+// convolve returns the distribution of the sum of two counts.
+func convolve(a, b []float64) []float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	c := make([]float64, len(a)+len(b)-1)
+	for i, x := range a {
+		for j, y := range b {
+			c[i+j] += x * y
+		}
+	}
+	return c
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+// odds converts a probability to odds, keeping it away from zero and one
+// so that no word is ruled in or out by the classifier alone.
+func odds(p float64) float64 {
+	if p < 0.01 {
+		p = 0.01
+	} else if p > 0.99 {
+		p = 0.99
+	}
+	return p / (1 - p)
+}
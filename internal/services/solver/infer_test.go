@@ -0,0 +1,138 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package solver
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// newTestService returns a service with the words as its dictionary and
+// an untrained classifier, so every unknown word has the same prior.
+func newTestService(words ...string) Service {
+	s := Service{dict: setOf(words), valid: map[string]bool{}, invalid: map[string]bool{}, checks: map[string]bool{}}
+	s.words = sortedSet(s.dict)
+	for _, word := range s.words {
+		s.masks = append(s.masks, letterMask(word))
+	}
+	return s
+}
+
+func TestInfer(t *testing.T) {
+	p, err := NewPuzzle("c", "hmnotu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestService("count", "cutch", "touch")
+
+	withoutTwoLetters := NewHints(p, []string{"count", "touch"})
+	withoutTwoLetters.TwoLetters = map[string]int{}
+
+	for _, tc := range []struct {
+		name     string
+		official *Hints
+		want     map[string]float64
+		conflict string // a conflict that must be reported, if any
+	}{
+		{
+			// the two-letter list tells count and cutch apart
+			name:     "unique",
+			official: NewHints(p, []string{"count", "touch"}),
+			want:     map[string]float64{"count": 1, "cutch": 0, "touch": 1},
+		},
+		{
+			// one of the two C5 words is in, but nothing says which
+			name:     "ambiguous",
+			official: withoutTwoLetters,
+			want:     map[string]float64{"count": 0.5, "cutch": 0.5, "touch": 1},
+		},
+		{
+			// the official list has a CH word that the dictionary doesn't
+			name:     "contradiction",
+			official: NewHints(p, []string{"chout", "touch"}),
+			want:     map[string]float64{"count": 0.5, "cutch": 0.5, "touch": 1},
+			conflict: "C: no choice of words matches the official counts",
+		},
+	} {
+		results, conflicts, err := s.Infer(context.Background(), p, tc.official)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		got := make(map[string]float64)
+		for _, l := range results {
+			got[l.Word] = l.Probability
+		}
+		for word, want := range tc.want {
+			if math.Abs(got[word]-want) > 1e-9 {
+				t.Errorf("%s: %s: got %v, want %v", tc.name, word, got[word], want)
+			}
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: got %d words, want %d", tc.name, len(got), len(tc.want))
+		}
+
+		if tc.conflict == "" && len(conflicts) != 0 {
+			t.Errorf("%s: got conflicts %q, want none", tc.name, conflicts)
+		} else if tc.conflict != "" {
+			found := false
+			for _, conflict := range conflicts {
+				found = found || strings.HasPrefix(conflict, tc.conflict)
+				if strings.Contains(conflict, "too many combinations") {
+					t.Errorf("%s: got conflict %q", tc.name, conflict)
+				}
+			}
+			if !found {
+				t.Errorf("%s: got conflicts %q, want %q", tc.name, conflicts, tc.conflict)
+			}
+		}
+	}
+}
+
+func TestElementary(t *testing.T) {
+	values := []float64{1, 2, 3}
+	for k, want := range []float64{1, 6, 11, 6} {
+		if got := elementary(values, k); got != want {
+			t.Errorf("elementary(%v, %d): got %v, want %v", values, k, got, want)
+		}
+	}
+}
+
+func TestInferOrder(t *testing.T) {
+	p, err := NewPuzzle("c", "hmnotu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestService("count", "cutch", "touch")
+	s.invalid["cutch"] = true
+	results, _, err := s.Infer(context.Background(), p, NewHints(p, []string{"count", "touch"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sort.SliceIsSorted(results, func(i, j int) bool { return results[i].Probability > results[j].Probability }) {
+		t.Errorf("results are not ordered by probability: %v", results)
+	}
+	if last := results[len(results)-1]; last.Word != "cutch" || last.Known != "invalid" {
+		t.Errorf("last result: got %+v, want cutch from the invalid list", last)
+	}
+}
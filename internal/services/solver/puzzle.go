@@ -0,0 +1,157 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package solver
+
+import (
+	"github.com/pkg/errors"
+	"strings"
+	"unicode"
+)
+
+// Puzzle is a validated set of puzzle letters.
+type Puzzle struct {
+	Center rune    // the required letter
+	Hex    [6]rune // the remaining letters
+}
+
+// NewPuzzle validates the center and hex letters and returns a Puzzle.
+// Letters are converted to lower case.
+func NewPuzzle(center, hex string) (Puzzle, error) {
+	if center == "" {
+		return Puzzle{}, errors.New("missing 'center'")
+	} else if hex == "" {
+		return Puzzle{}, errors.New("missing 'hex'")
+	}
+
+	var p Puzzle
+	for i, r := range center {
+		if i > 0 || !unicode.IsLetter(r) {
+			return Puzzle{}, errors.New("invalid 'center'")
+		}
+		p.Center = unicode.ToLower(r)
+	}
+
+	var hexLetters []rune
+	for _, r := range hex {
+		if len(hexLetters) == 6 || !unicode.IsLetter(r) {
+			return Puzzle{}, errors.New("invalid 'hex'")
+		}
+		r = unicode.ToLower(r)
+		if r == p.Center {
+			return Puzzle{}, errors.New("duplicate 'hex'")
+		}
+		for _, h := range hexLetters {
+			if r == h {
+				return Puzzle{}, errors.New("duplicate 'hex'")
+			}
+		}
+		hexLetters = append(hexLetters, r)
+	}
+	if len(hexLetters) != 6 {
+		return Puzzle{}, errors.New("invalid 'hex'")
+	}
+	copy(p.Hex[:], hexLetters)
+
+	return p, nil
+}
+
+// Letters returns all seven letters with the center letter first.
+func (p Puzzle) Letters() [7]rune {
+	return [7]rune{p.Center, p.Hex[0], p.Hex[1], p.Hex[2], p.Hex[3], p.Hex[4], p.Hex[5]}
+}
+
+// Contains returns true if the letter is one of the puzzle's letters.
+func (p Puzzle) Contains(r rune) bool {
+	for _, ch := range p.Letters() {
+		if r == ch {
+			return true
+		}
+	}
+	return false
+}
+
+// Accepts returns true if the word uses the center letter and no letters
+// from outside the puzzle. It does not check the word's length.
+func (p Puzzle) Accepts(word string) bool {
+	if !strings.ContainsRune(word, p.Center) {
+		return false
+	}
+	for _, r := range word {
+		if !p.Contains(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsPangram returns true if the word uses every letter in the puzzle.
+func (p Puzzle) IsPangram(word string) bool {
+	for _, ch := range p.Letters() {
+		if !strings.ContainsRune(word, ch) {
+			return false
+		}
+	}
+	return true
+}
+
+// Score returns the points for a word using the Spelling Bee rules:
+// four-letter words are worth one point, longer words are worth one
+// point per letter, and pangrams earn a bonus of seven points.
+func (p Puzzle) Score(word string) int {
+	n := len([]rune(word))
+	if n < 4 {
+		return 0
+	}
+	points := n
+	if n == 4 {
+		points = 1
+	}
+	if p.IsPangram(word) {
+		points += 7
+	}
+	return points
+}
+
+// Reasons a guess can be rejected.
+const (
+	ReasonTooShort      = "too-short"      // fewer than four letters
+	ReasonMissingCenter = "missing-center" // doesn't use the center letter
+	ReasonBadLetter     = "bad-letter"     // uses a letter outside the puzzle
+	ReasonUnknownWord   = "unknown-word"   // not in the word list
+	ReasonKnownRejected = "known-rejected" // in the list of words the game rejects
+	ReasonDuplicate     = "duplicate"      // already found
+)
+
+// Check returns the reason the word breaks the puzzle's rules, or an
+// empty string if it doesn't. It does not check the word list.
+func (p Puzzle) Check(word string) string {
+	if len([]rune(word)) < 4 {
+		return ReasonTooShort
+	}
+	for _, r := range word {
+		if !p.Contains(r) {
+			return ReasonBadLetter
+		}
+	}
+	if !strings.ContainsRune(word, p.Center) {
+		return ReasonMissingCenter
+	}
+	return ""
+}
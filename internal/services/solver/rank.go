@@ -0,0 +1,66 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package solver
+
+import "math"
+
+// Rank is a named score threshold.
+type Rank struct {
+	Name   string
+	Points int // the points needed to reach the rank
+}
+
+// ranks are the game's ranks and the percent of the total points needed for each.
+var ranks = []struct {
+	name    string
+	percent float64
+}{
+	{"Beginner", 0},
+	{"Good Start", 2},
+	{"Moving Up", 5},
+	{"Good", 8},
+	{"Solid", 15},
+	{"Nice", 25},
+	{"Great", 40},
+	{"Amazing", 50},
+	{"Genius", 70},
+	{"Queen Bee", 100},
+}
+
+// Ranks returns the ranks for a puzzle worth total points, lowest first.
+func Ranks(total int) []Rank {
+	var list []Rank
+	for _, r := range ranks {
+		list = append(list, Rank{Name: r.name, Points: int(math.Round(float64(total) * r.percent / 100))})
+	}
+	return list
+}
+
+// RankOf returns the rank reached with points in a puzzle worth total points.
+// It also returns the next rank; hasNext is false at Queen Bee.
+func RankOf(points, total int) (rank Rank, next Rank, hasNext bool) {
+	list := Ranks(total)
+	for i := 1; i < len(list); i++ {
+		if points < list[i].Points {
+			return list[i-1], list[i], true
+		}
+	}
+	return list[len(list)-1], Rank{}, false
+}
@@ -0,0 +1,82 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package solver
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// Search returns the dictionary words that match every part of the request.
+func (s Service) Search(ctx context.Context, request SearchRequest) (*SearchResponse, error) {
+	q := &query{
+		prefix: []rune(strings.ToLower(request.Prefix)),
+		exact:  request.Exact,
+		min:    request.MinLength,
+		max:    request.MaxLength,
+		limit:  request.Limit,
+	}
+	if request.Pattern != "" {
+		q.pattern = []rune(strings.ToLower(request.Pattern))
+		// collapse runs of stars, which match the same words as one
+		for strings.Contains(string(q.pattern), "**") {
+			q.pattern = []rune(strings.ReplaceAll(string(q.pattern), "**", "*"))
+		}
+	}
+	if request.Letters != "" {
+		q.useBag, q.bag = true, make(map[rune]int)
+		for _, r := range strings.ToLower(request.Letters) {
+			if r == '?' {
+				q.blanks++
+			} else {
+				q.bag[r]++
+			}
+		}
+	} else if request.Exact {
+		return nil, errors.New("'exact' needs 'letters'")
+	}
+	if request.Center != "" || request.Hex != "" {
+		puzzle, err := NewPuzzle(request.Center, request.Hex)
+		if err != nil {
+			return nil, err
+		}
+		q.allowed, q.center = make(map[rune]bool), puzzle.Center
+		for _, r := range puzzle.Letters() {
+			q.allowed[r] = true
+		}
+	}
+
+	if q.min < 0 || q.max < 0 || request.Limit < 0 {
+		return nil, errors.New("'minLength', 'maxLength', and 'limit' must not be negative")
+	} else if q.max > 0 && q.max < q.min {
+		return nil, errors.New("'maxLength' must not be less than 'minLength'")
+	} else if len(q.prefix) == 0 && q.pattern == nil && !q.useBag && q.allowed == nil && q.min == 0 && q.max == 0 {
+		return nil, errors.New("missing query: set at least one of 'prefix', 'pattern', 'letters', 'center', 'minLength', or 'maxLength'")
+	} else if len(q.pattern) > 63 {
+		return nil, errors.New("'pattern' must not be longer than 63 characters")
+	}
+
+	words, truncated, err := s.index.search(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return &SearchResponse{Words: words, Truncated: truncated, Dictionary: s.version}, nil
+}
@@ -21,119 +21,548 @@ package solver
 
 import (
 	"context"
+	"embed"
+	"github.com/mdhender/queenie/internal/wordlist"
 	"github.com/pkg/errors"
-	"os"
 	"sort"
 	"strings"
-	"unicode"
 )
 
+// data holds the default word lists, so that the solver works without any files.
+//
+//go:embed data
+var data embed.FS
+
 type Service struct {
 	dict    map[string]bool
 	invalid map[string]bool
 	valid   map[string]bool
 	checks  map[string]bool
 	words   []string
+	masks   []uint32 // the letter mask of each word in words
+	index   *dawg    // the words as a graph, for searching
+	// frequency is the frequency rank of each word, or nil if there is no frequency file
+	frequency map[string]int
+	// classifier estimates the chance that unverified words are accepted
+	classifier *Classifier
+	// tags are the word list's tags for each word that has any
+	tags map[string][]string
+	// reports describe how each word list loaded
+	reports []*wordlist.Report
+	// hash identifies the contents of the word lists
+	hash string
+	// version is the hash and load time reported in responses
+	version DictionaryVersion
 }
 
-func NewService() (Service, error) {
-	s := Service{}
+// Files names the word lists to layer over the embedded ones.
+// Lists with empty names are skipped; named lists must exist.
+type Files struct {
+	Wordlist  string // more dictionary words
+	Valid     string // words known to be accepted
+	Invalid   string // words known to be rejected
+	Checks    string // words already checked
+	Frequency string // frequency ranks; see LoadFrequencies
+	// Snapshot is a compiled snapshot of the lists (see WriteSnapshot).
+	// If it is set, it is loaded instead of the embedded lists and the files.
+	Snapshot string
+}
 
-	// load the words file sourced from https://github.com/dwyl/english-words and other places
-	var err error
-	if s.dict, err = loadWords("wordlist.txt"); err != nil {
-		return Service{}, err
-	} else if s.invalid, err = loadWords("invalid.txt"); err != nil {
-		return Service{}, err
-	} else if s.valid, err = loadWords("valid.txt"); err != nil {
-		return Service{}, err
-	} else if s.checks, err = loadWords("checks.txt"); err != nil {
+// NewService loads the embedded word lists with the files layered on top.
+// Words in the files are added to the embedded lists, and a word in the
+// valid or invalid file overrides the embedded lists' verdict on it.
+func NewService(files Files) (Service, error) {
+	if files.Snapshot != "" {
+		s, err := readSnapshot(files.Snapshot)
+		if err != nil {
+			return Service{}, err
+		}
+		s.index = newDawg(s.words)
+		s.classifier = NewClassifier(s.valid, s.invalid)
+		s.recordVersion()
+		return s, nil
+	}
+
+	s := Service{tags: make(map[string][]string)}
+
+	// the embedded words are expanded from SCOWL's en_US-web dictionary; see data/NOTICE.md
+	embedded, external, err := s.load("wordlist.txt.gz", files.Wordlist)
+	if err != nil {
 		return Service{}, err
 	}
+	entries := append(embedded, external...)
+	s.dict = wordlist.Set(entries)
+	// a later list replaces the tags, so a word it writes in lower case is no longer a name
+	for _, e := range entries {
+		if len(e.Tags) != 0 {
+			s.tags[e.Word] = e.Tags
+		} else {
+			delete(s.tags, e.Word)
+		}
+	}
+
+	var lists [3][2]map[string]bool // embedded and external sets for valid, invalid, and checks
+	for i, list := range []struct{ embedded, name string }{
+		{"valid.txt", files.Valid},
+		{"invalid.txt", files.Invalid},
+		{"checks.txt", files.Checks},
+	} {
+		embedded, external, err := s.load(list.embedded, list.name)
+		if err != nil {
+			return Service{}, err
+		}
+		lists[i] = [2]map[string]bool{wordlist.Set(embedded), wordlist.Set(external)}
+	}
+	s.valid, s.invalid, s.checks = lists[0][0], lists[1][0], lists[2][0]
+	for word := range lists[0][1] {
+		s.valid[word] = true
+		delete(s.invalid, word)
+	}
+	for word := range lists[1][1] {
+		if !lists[0][1][word] {
+			s.invalid[word] = true
+			delete(s.valid, word)
+		}
+	}
+	for word := range lists[2][1] {
+		s.checks[word] = true
+	}
+
+	// without a frequency file, use the word lists' frequency column if they have one
+	if files.Frequency == "" {
+		s.frequency = rankEntries(entries)
+	} else {
+		var report *wordlist.Report
+		if s.frequency, report, err = LoadFrequencies(files.Frequency); err != nil {
+			return Service{}, err
+		}
+		s.reports = append(s.reports, report)
+	}
+
+	// words known to be accepted are answers even when the word list
+	// doesn't have them, so Solve returns them and Check accepts them.
+	for word := range s.valid {
+		s.dict[word] = true
+	}
 
 	for word := range s.dict {
 		s.words = append(s.words, word)
 	}
 	sort.Strings(s.words)
+	for _, word := range s.words {
+		s.masks = append(s.masks, letterMask(word))
+	}
+	s.hash = s.contentHash()
+	s.index = newDawg(s.words)
+
+	s.classifier = NewClassifier(s.valid, s.invalid)
+	s.recordVersion()
 
 	return s, nil
 }
 
-func (s Service) Solve(ctx context.Context, request PuzzleRequest) (*SolutionResponse, error) {
-	// consider rejecting unknown fields (https://www.alexedwards.net/blog/how-to-properly-parse-a-json-request-body)
-	if request.Center == "" {
-		return nil, errors.New("missing 'center'")
-	} else if request.Hex == "" {
-		return nil, errors.New("missing 'hex'")
+// load reads an embedded list and, if name isn't empty, the file to layer
+// on top of it. The load reports are added to the service's reports.
+func (s *Service) load(embeddedName, name string) (embedded, external []wordlist.Entry, err error) {
+	fp, err := data.Open("data/" + embeddedName)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer fp.Close()
+	embedded, report, err := wordlist.Read(fp, "embedded "+embeddedName)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.reports = append(s.reports, report)
+	if name == "" {
+		return embedded, nil, nil
 	}
+	external, report, err = wordlist.Load(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.reports = append(s.reports, report)
+	return embedded, external, nil
+}
 
-	var centerLetter rune
-	for i, r := range request.Center {
-		if i > 0 || !unicode.IsLetter(r) {
-			return nil, errors.New("invalid 'center'")
+// History is a past puzzle and its official answers.
+type History struct {
+	Puzzle  Puzzle
+	Answers []string
+	// Complete is true if Answers is the full official list.
+	Complete bool
+}
+
+// WithHistory returns a copy of the service with its classifier trained on
+// past puzzles as well as the word lists. Official answers count as
+// accepted; for complete puzzles, the dictionary words that weren't
+// answers count as rejected. The word lists win where they disagree.
+func (s Service) WithHistory(ctx context.Context, history []History) (Service, error) {
+	accepted, rejected := make(map[string]bool), make(map[string]bool)
+	for word := range s.valid {
+		accepted[word] = true
+	}
+	for word := range s.invalid {
+		rejected[word] = true
+	}
+	for _, h := range history {
+		official := make(map[string]bool)
+		for _, word := range h.Answers {
+			official[word] = true
+			if !s.invalid[word] {
+				accepted[word] = true
+			}
 		}
-		centerLetter = unicode.ToLower(r)
+		if !h.Complete {
+			continue
+		}
+		words, err := s.scan(ctx, h.Puzzle)
+		if err != nil {
+			return Service{}, err
+		}
+		for _, word := range words {
+			if !official[word] && !s.valid[word] {
+				rejected[word] = true
+			}
+		}
+	}
+	// a word accepted in one puzzle and rejected in another is left out
+	for word := range accepted {
+		if rejected[word] && !s.valid[word] && !s.invalid[word] {
+			delete(accepted, word)
+			delete(rejected, word)
+		}
+	}
+	s.classifier = NewClassifier(accepted, rejected)
+	return s, nil
+}
+
+func (s Service) Solve(ctx context.Context, request PuzzleRequest) (*SolutionResponse, error) {
+	puzzle, err := NewPuzzle(request.Center, request.Hex)
+	if err != nil {
+		return nil, err
+	}
+
+	words, err := s.scan(ctx, puzzle)
+	if err != nil {
+		return nil, err
 	}
+	//sort.Strings(words)
 
-	var hexLetters []rune
-	for i, r := range request.Hex {
-		if i > 6 || !unicode.IsLetter(r) {
-			return nil, errors.New("invalid 'hex'")
+	// leave out the words that are too obscure, unless they're known to be accepted
+	if request.MaxRank > 0 && s.frequency != nil {
+		var common []string
+		for _, word := range words {
+			if rank, ok := s.frequency[word]; s.valid[word] || (ok && rank <= request.MaxRank) {
+				common = append(common, word)
+			}
 		}
-		r = unicode.ToLower(r)
-		if r == centerLetter {
-			return nil, errors.New("duplicate 'hex'")
+		words = common
+	}
+
+	// leave out the words with excluded tags, unless they're known to be accepted
+	if len(request.Exclude) != 0 {
+		exclude := make(map[string]bool)
+		for _, tag := range request.Exclude {
+			exclude[strings.ToLower(strings.TrimSpace(tag))] = true
 		}
-		for _, h := range hexLetters {
-			if r == h {
-				return nil, errors.New("duplicate 'hex'")
+		var kept []string
+		for _, word := range words {
+			excluded := false
+			for _, tag := range s.tags[word] {
+				excluded = excluded || exclude[tag]
+			}
+			if s.valid[word] || !excluded {
+				kept = append(kept, word)
 			}
 		}
-		hexLetters = append(hexLetters, r)
+		words = kept
 	}
-	if len(hexLetters) != 6 {
-		return nil, errors.New("invalid 'hex'")
+
+	tags := []WordTags{}
+	for _, word := range words {
+		if len(s.tags[word]) != 0 {
+			tags = append(tags, WordTags{Word: word, Tags: s.tags[word]})
+		}
 	}
 
-	// the first letter in it must be the puzzle's center letter.
-	letters := [7]rune{centerLetter, hexLetters[0], hexLetters[1], hexLetters[2], hexLetters[3], hexLetters[4], hexLetters[5]}
+	frequency := []WordFrequency{}
+	if s.frequency != nil {
+		for _, word := range words {
+			frequency = append(frequency, WordFrequency{Word: word, Rank: s.frequency[word]})
+		}
+	}
 
-	var words []string
-	for _, word := range s.words {
-		if !strings.ContainsRune(word, centerLetter) {
-			continue
+	unverified := []WordProbability{}
+	for _, word := range words {
+		if !s.valid[word] && !s.invalid[word] {
+			unverified = append(unverified, WordProbability{Word: word, Probability: s.classifier.Probability(word)})
 		}
-		w := word
-		// remove all the allowed characters from the word
-		for _, ch := range letters {
-			w = strings.ReplaceAll(w, string(ch), "")
+	}
+	sort.SliceStable(unverified, func(i, j int) bool { return unverified[i].Probability > unverified[j].Probability })
+
+	return &SolutionResponse{
+		Words:      words,
+		Frequency:  frequency,
+		Tags:       tags,
+		Unverified: unverified,
+		Dictionary: s.version,
+	}, nil
+}
+
+// scan returns the words in the dictionary that satisfy the puzzle.
+func (s Service) scan(ctx context.Context, puzzle Puzzle) ([]string, error) {
+	// the letter masks settle most words without looking at their letters
+	var allowed uint32
+	useMasks := true
+	for _, r := range puzzle.Letters() {
+		if r < 'a' || r > 'z' {
+			useMasks = false
 		}
-		// result must be empty (ie, must not contain any other letters)
-		if len(w) == 0 {
+		allowed |= letterMask(string(r))
+	}
+	center := letterMask(string(puzzle.Center))
+
+	var words []string
+	for i, word := range s.words {
+		// stop scanning if the caller has given up on us
+		if i%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		if useMasks {
+			if s.masks[i]&^allowed == 0 && s.masks[i]&center != 0 {
+				words = append(words, word)
+			}
+		} else if puzzle.Accepts(word) {
 			words = append(words, word)
 		}
 	}
-	//sort.Strings(words)
+	return words, nil
+}
 
-	return &SolutionResponse{
-		Words: words,
+// Answers returns the words that Check accepts for the puzzle:
+// the solution without the words known to be rejected.
+func (s Service) Answers(ctx context.Context, puzzle Puzzle) ([]string, error) {
+	words, err := s.scan(ctx, puzzle)
+	if err != nil {
+		return nil, err
+	}
+	var answers []string
+	for _, word := range words {
+		if !s.invalid[word] {
+			answers = append(answers, word)
+		}
+	}
+	return answers, nil
+}
+
+// Check checks a guess against the puzzle rules and the word lists.
+// Rejections are reported in the response rather than as errors;
+// errors are only returned for invalid puzzles.
+func (s Service) Check(ctx context.Context, request CheckRequest) (*CheckResponse, error) {
+	puzzle, err := NewPuzzle(request.Center, request.Hex)
+	if err != nil {
+		return nil, err
+	}
+
+	word := strings.ToLower(wordlist.Normalize(strings.TrimSpace(request.Word)))
+	reason := puzzle.Check(word)
+	if reason == "" {
+		for _, found := range request.Found {
+			if strings.ToLower(strings.TrimSpace(found)) == word {
+				reason = ReasonDuplicate
+				break
+			}
+		}
+	}
+	if reason == "" && s.invalid[word] {
+		reason = ReasonKnownRejected
+	} else if reason == "" && !s.dict[word] {
+		reason = ReasonUnknownWord
+	}
+	if reason != "" {
+		return &CheckResponse{Reason: reason, Dictionary: s.version}, nil
+	}
+
+	return &CheckResponse{
+		Accepted:   true,
+		Score:      puzzle.Score(word),
+		Pangram:    puzzle.IsPangram(word),
+		Dictionary: s.version,
 	}, nil
 }
 
-func loadWords(filename string) (map[string]bool, error) {
-	raw, err := os.ReadFile(filename)
+// Progress returns the score and rank for the words found so far,
+// along with hints for the words that remain.
+// The remaining words themselves are never returned.
+func (s Service) Progress(ctx context.Context, request ProgressRequest) (*ProgressResponse, error) {
+	puzzle, err := NewPuzzle(request.Center, request.Hex)
+	if err != nil {
+		return nil, err
+	}
+	answers, err := s.Answers(ctx, puzzle)
 	if err != nil {
 		return nil, err
 	}
 
-	words := make(map[string]bool)
-	for _, word := range strings.Split(string(raw), "\n") {
-		// must be at least four characters
-		if len(word) < 4 {
+	response := &ProgressResponse{Dictionary: s.version}
+	isAnswer := make(map[string]bool)
+	for _, word := range answers {
+		isAnswer[word] = true
+		response.TotalPoints += puzzle.Score(word)
+	}
+	response.TotalWords = len(answers)
+
+	found := make(map[string]bool)
+	for _, word := range request.Found {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" || found[word] {
+			continue
+		}
+		found[word] = true
+		if !isAnswer[word] {
+			response.Rejected = append(response.Rejected, word)
 			continue
 		}
-		words[strings.ToLower(word)] = true
+		response.Words++
+		response.Points += puzzle.Score(word)
 	}
 
-	return words, nil
+	rank, next, hasNext := RankOf(response.Points, response.TotalPoints)
+	response.Rank = rank.Name
+	if hasNext {
+		response.NextRank = next.Name
+		response.PointsToNextRank = next.Points - response.Points
+	}
+
+	var remaining []string
+	for _, word := range answers {
+		if !found[word] {
+			remaining = append(remaining, word)
+		}
+	}
+	hints := NewHints(puzzle, remaining)
+	response.RemainingPoints = hints.Points
+	response.RemainingWords = hints.Words
+	response.RemainingPangrams = hints.Pangrams
+	byLength := make(map[int]int)
+	for _, letter := range hints.Letters() {
+		sum := 0
+		for _, n := range hints.Lengths() {
+			if count := hints.Grid[letter][n]; count != 0 {
+				response.RemainingGrid = append(response.RemainingGrid, GridCount{Letter: string(letter), Length: n, Count: count})
+				byLength[n] += count
+				sum += count
+			}
+		}
+		response.RemainingByLetter = append(response.RemainingByLetter, LetterCount{Letter: string(letter), Count: sum})
+	}
+	for _, n := range hints.Lengths() {
+		response.RemainingByLength = append(response.RemainingByLength, LengthCount{Length: n, Count: byLength[n]})
+	}
+	response.RemainingTwoLetters = prefixCounts(hints)
+
+	return response, nil
+}
+
+// Hints returns the counts from the official hints page for the
+// puzzle's answers. Like Progress, it leaves out known-rejected words.
+func (s Service) Hints(ctx context.Context, request HintsRequest) (*HintsResponse, error) {
+	puzzle, err := NewPuzzle(request.Center, request.Hex)
+	if err != nil {
+		return nil, err
+	}
+	hints, err := s.AnswerHints(ctx, puzzle)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &HintsResponse{
+		Words:           hints.Words,
+		Points:          hints.Points,
+		Pangrams:        hints.Pangrams,
+		PerfectPangrams: hints.PerfectPangrams,
+		TwoLetters:      prefixCounts(hints),
+		Dictionary:      s.version,
+	}
+	for _, letter := range hints.Letters() {
+		for _, n := range hints.Lengths() {
+			if count := hints.Grid[letter][n]; count != 0 {
+				response.Grid = append(response.Grid, GridCount{Letter: string(letter), Length: n, Count: count})
+			}
+		}
+	}
+	return response, nil
+}
+
+// AnswerHints returns the hints for the puzzle's answers, for comparing
+// against the official hints page.
+func (s Service) AnswerHints(ctx context.Context, puzzle Puzzle) (*Hints, error) {
+	answers, err := s.Answers(ctx, puzzle)
+	if err != nil {
+		return nil, err
+	}
+	return NewHints(puzzle, answers), nil
+}
+
+// prefixCounts returns the two-letter counts in order of the prefixes.
+func prefixCounts(hints *Hints) []PrefixCount {
+	var prefixes []string
+	for prefix := range hints.TwoLetters {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	var counts []PrefixCount
+	for _, prefix := range prefixes {
+		counts = append(counts, PrefixCount{Prefix: prefix, Count: hints.TwoLetters[prefix]})
+	}
+	return counts
+}
+
+// ParseFound reads the found words from text copied from the game
+// and returns the player's progress. Puzzle letters that aren't given
+// are inferred from the found words.
+func (s Service) ParseFound(ctx context.Context, request ParseFoundRequest) (*ParseFoundResponse, error) {
+	found, claimed := ParseFoundText(request.Text)
+	if len(found) == 0 {
+		return nil, errors.New("no found words in 'text'")
+	}
+
+	center, hex := request.Center, request.Hex
+	if hex == "" {
+		var err error
+		if center, hex, err = InferPuzzle(found, center); err != nil {
+			return nil, err
+		}
+	} else if center == "" {
+		var err error
+		if center, err = InferCenter(found, hex); err != nil {
+			return nil, err
+		}
+	}
+
+	progress, err := s.Progress(ctx, ProgressRequest{Center: center, Hex: hex, Found: found})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ParseFoundResponse{
+		Center:   strings.ToLower(center),
+		Hex:      strings.ToLower(hex),
+		Found:    found,
+		Claimed:  claimed,
+		Progress: *progress,
+	}, nil
+}
+
+// Words returns the number of words in the dictionary.
+func (s Service) Words() int {
+	return len(s.words)
+}
+
+// Reports returns the load report for each word list.
+func (s Service) Reports() []*wordlist.Report {
+	return s.reports
 }
@@ -0,0 +1,261 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package solver
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/mdhender/queenie/internal/wordlist"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A snapshot is the word lists compiled into a single binary file so that
+// the service can start without parsing and sorting the text lists.
+// The file is the magic string, the format version as a big-endian
+// uint16, the SHA-256 of the embedded word lists it was compiled with,
+// and then the gzip-compressed, gob-encoded snapshot.
+const (
+	snapshotMagic = "QUEENIE-DICT"
+	// SnapshotVersion is the snapshot format version that WriteSnapshot writes
+	// and NewService reads.
+	SnapshotVersion = 2
+)
+
+// ErrStaleSnapshot is returned for a snapshot written in another format
+// version or compiled from different embedded word lists than the ones
+// in this binary.
+var ErrStaleSnapshot = errors.New("snapshot is out of date; run queenie dict compile")
+
+// snapshot is the contents of a snapshot file.
+type snapshot struct {
+	Words     []string // sorted, including the valid words
+	Masks     []uint32 // the letter mask of each word in Words
+	Tags      map[string][]string
+	Valid     []string
+	Invalid   []string
+	Checks    []string
+	Frequency map[string]int
+	Hash      string // the content hash of everything above
+}
+
+// WriteSnapshot writes the service's word lists as a snapshot.
+func (s Service) WriteSnapshot(w io.Writer) error {
+	if _, err := io.WriteString(w, snapshotMagic); err != nil {
+		return err
+	} else if err := binary.Write(w, binary.BigEndian, uint16(SnapshotVersion)); err != nil {
+		return err
+	} else if _, err := w.Write(embeddedHash()); err != nil {
+		return err
+	}
+	zw, err := gzip.NewWriterLevel(w, gzip.BestSpeed)
+	if err != nil {
+		return err
+	}
+	err = gob.NewEncoder(zw).Encode(snapshot{
+		Words:     s.words,
+		Masks:     s.masks,
+		Tags:      s.tags,
+		Valid:     sortedSet(s.valid),
+		Invalid:   sortedSet(s.invalid),
+		Checks:    sortedSet(s.checks),
+		Frequency: s.frequency,
+		Hash:      s.hash,
+	})
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// CheckSnapshot reads the header of a snapshot file. It returns
+// ErrStaleSnapshot if the snapshot must be compiled again.
+func CheckSnapshot(name string) error {
+	fp, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+	return readSnapshotHeader(bufio.NewReader(fp), name)
+}
+
+// readSnapshot loads a service from a snapshot file.
+// It fails if the snapshot is stale or the contents don't match the hash.
+func readSnapshot(name string) (Service, error) {
+	fp, err := os.Open(name)
+	if err != nil {
+		return Service{}, err
+	}
+	defer fp.Close()
+	r := bufio.NewReader(fp)
+	if err := readSnapshotHeader(r, name); err != nil {
+		return Service{}, err
+	}
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return Service{}, fmt.Errorf("%s: %w", name, err)
+	}
+	defer zr.Close()
+	var snap snapshot
+	if err := gob.NewDecoder(zr).Decode(&snap); err != nil {
+		return Service{}, fmt.Errorf("%s: %w", name, err)
+	} else if len(snap.Masks) != len(snap.Words) {
+		return Service{}, fmt.Errorf("%s: snapshot has %d masks for %d words", name, len(snap.Masks), len(snap.Words))
+	}
+
+	s := Service{
+		dict:      make(map[string]bool, len(snap.Words)),
+		valid:     setOf(snap.Valid),
+		invalid:   setOf(snap.Invalid),
+		checks:    setOf(snap.Checks),
+		words:     snap.Words,
+		masks:     snap.Masks,
+		frequency: snap.Frequency,
+		tags:      snap.Tags,
+	}
+	for _, word := range snap.Words {
+		s.dict[word] = true
+	}
+	if s.tags == nil {
+		s.tags = make(map[string][]string)
+	}
+	if s.hash = s.contentHash(); s.hash != snap.Hash {
+		return Service{}, fmt.Errorf("%s: snapshot contents don't match its hash", name)
+	}
+	s.reports = append(s.reports, &wordlist.Report{File: name, Words: len(s.words), Rejected: map[string]int{}})
+	return s, nil
+}
+
+// readSnapshotHeader reads the magic string, the version, and the hash
+// of the embedded lists, and checks them against this binary.
+func readSnapshotHeader(r io.Reader, name string) error {
+	magic := make([]byte, len(snapshotMagic))
+	var version uint16
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != snapshotMagic {
+		return fmt.Errorf("%s: not a dictionary snapshot", name)
+	} else if err := binary.Read(r, binary.BigEndian, &version); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	} else if version != SnapshotVersion {
+		return fmt.Errorf("%s: version %d: %w", name, version, ErrStaleSnapshot)
+	}
+	hash := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(r, hash); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	} else if string(hash) != string(embeddedHash()) {
+		return fmt.Errorf("%s: compiled from different embedded lists: %w", name, ErrStaleSnapshot)
+	}
+	return nil
+}
+
+// dataHash caches the hash of the embedded data.
+var dataHash struct {
+	once sync.Once
+	hash []byte
+}
+
+// embeddedHash returns the SHA-256 of the names and contents of the
+// files in the embedded data directory.
+func embeddedHash() []byte {
+	dataHash.once.Do(func() {
+		h := sha256.New()
+		err := fs.WalkDir(data, "data", func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			b, err := data.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "%s\x00%d\x00", path, len(b))
+			h.Write(b)
+			return nil
+		})
+		if err != nil {
+			// the embedded files are compiled in, so this can't happen
+			panic(err)
+		}
+		dataHash.hash = h.Sum(nil)
+	})
+	return dataHash.hash
+}
+
+// contentHash returns a hash of the words, tags, curated lists, and
+// frequency ranks. Services with the same lists have the same hash,
+// however they were loaded.
+func (s Service) contentHash() string {
+	h := sha256.New()
+	for _, word := range s.words {
+		io.WriteString(h, word)
+		for _, tag := range s.tags[word] {
+			io.WriteString(h, "\t"+tag)
+		}
+		if rank, ok := s.frequency[word]; ok {
+			io.WriteString(h, "\t#"+strconv.Itoa(rank))
+		}
+		io.WriteString(h, "\n")
+	}
+	for _, list := range []map[string]bool{s.valid, s.invalid, s.checks} {
+		io.WriteString(h, "--\n")
+		io.WriteString(h, strings.Join(sortedSet(list), "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// letterMask returns a bit for each letter a to z in the word,
+// plus bit 26 if the word has any other character.
+func letterMask(word string) uint32 {
+	var mask uint32
+	for _, r := range word {
+		if 'a' <= r && r <= 'z' {
+			mask |= 1 << (r - 'a')
+		} else {
+			mask |= 1 << 26
+		}
+	}
+	return mask
+}
+
+func sortedSet(set map[string]bool) []string {
+	words := make([]string, 0, len(set))
+	for word := range set {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	return words
+}
+
+func setOf(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
@@ -0,0 +1,65 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package solver
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	s, err := NewService(Files{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := s.WriteSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+
+	name := filepath.Join(dir, "current.snap")
+	if err := os.WriteFile(name, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	} else if err := CheckSnapshot(name); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	loaded, err := NewService(Files{Snapshot: name})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	} else if loaded.Version().Hash != s.Version().Hash {
+		t.Errorf("loaded hash %s, want %s", loaded.Version().Hash, s.Version().Hash)
+	}
+
+	// a snapshot compiled from other embedded lists differs in the header
+	stale := append([]byte{}, buf.Bytes()...)
+	stale[len(snapshotMagic)+2] ^= 0xff
+	name = filepath.Join(dir, "stale.snap")
+	if err := os.WriteFile(name, stale, 0644); err != nil {
+		t.Fatal(err)
+	} else if err := CheckSnapshot(name); !errors.Is(err, ErrStaleSnapshot) {
+		t.Errorf("check: got %v, want %v", err, ErrStaleSnapshot)
+	} else if _, err := NewService(Files{Snapshot: name}); !errors.Is(err, ErrStaleSnapshot) {
+		t.Errorf("load: got %v, want %v", err, ErrStaleSnapshot)
+	}
+}
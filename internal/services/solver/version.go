@@ -0,0 +1,68 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package solver
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// seen holds the dictionary versions loaded since startup, oldest first.
+var seen struct {
+	sync.Mutex
+	versions []DictionaryVersion
+}
+
+// recordVersion sets the service's dictionary version from its content
+// hash and the current time, and adds it to the versions seen since
+// startup. Reloading lists with the same contents keeps the first version.
+func (s *Service) recordVersion() {
+	s.version = DictionaryVersion{
+		Hash:     s.hash,
+		LoadedAt: time.Now().UTC().Format(time.RFC3339),
+		Words:    len(s.words),
+	}
+	seen.Lock()
+	defer seen.Unlock()
+	for _, v := range seen.versions {
+		if v.Hash == s.hash {
+			s.version = v
+			return
+		}
+	}
+	seen.versions = append(seen.versions, s.version)
+}
+
+// Version returns the version of the service's word lists.
+func (s Service) Version() DictionaryVersion {
+	return s.version
+}
+
+// Versions returns the version of the word lists in use and every
+// version loaded since startup.
+func (s Service) Versions(ctx context.Context, request VersionsRequest) (*VersionsResponse, error) {
+	seen.Lock()
+	defer seen.Unlock()
+	return &VersionsResponse{
+		Current: s.version,
+		Seen:    append([]DictionaryVersion{}, seen.versions...),
+	}, nil
+}
@@ -0,0 +1,352 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package wordlist loads word lists in the formats Queenie's sources use.
+//
+// A list has one entry per line. Blank lines and lines starting with #
+// are ignored. An entry is a word, optionally followed by tab-separated
+// metadata columns: source, frequency, and comma-separated tags.
+// Lines may end in CRLF, and files may be gzip-compressed.
+//
+// Words are normalized to Unicode NFC and typographic apostrophes are
+// replaced with plain ones. Lines that can't be used are counted in the
+// load report rather than silently dropped.
+//
+// Each word is also classified from the way it is written (see Classify),
+// so that proper nouns and acronyms can be told apart after lower-casing.
+//
+// Frequency lists (see ReadFrequencies) are read the same way, but an
+// entry is a word optionally followed by whitespace and a count.
+package wordlist
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"golang.org/x/text/unicode/norm"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MinLength is the shortest word, in letters, that a list keeps.
+const MinLength = 4
+
+// Reasons a line is rejected.
+const (
+	RejectTooShort   = "too short"
+	RejectCharacters = "invalid characters"
+	RejectFrequency  = "bad frequency"
+)
+
+// Tags added by classifying the word as it appears in the list.
+const (
+	TagProper     = "proper"     // capitalized, like "Boston"
+	TagAcronym    = "acronym"    // all capitals, like "NASA"
+	TagHyphenated = "hyphenated" // contains a hyphen, like "x-ray"
+	TagApostrophe = "apostrophe" // contains an apostrophe, like "don't"
+)
+
+// Entry is a word from a list along with its metadata.
+type Entry struct {
+	// Word is the normalized word in lower case.
+	Word string
+	// Original is the normalized word with its case kept.
+	Original string
+	// Source is where the word came from. It is empty if not given.
+	Source string
+	// Frequency is how common the word is. It is zero if not given.
+	Frequency float64
+	// Tags are labels for the word: the tags column, if any, followed by
+	// the tags from Classify.
+	Tags []string
+}
+
+// Report describes what happened while loading a list.
+type Report struct {
+	File       string
+	Lines      int            // lines read, including comments
+	Words      int            // entries loaded
+	Comments   int            // blank and comment lines
+	Duplicates int            // entries for a word already loaded
+	Rejected   map[string]int // lines rejected, by reason
+}
+
+// RejectedLines returns the total number of rejected lines.
+func (r *Report) RejectedLines() int {
+	n := 0
+	for _, count := range r.Rejected {
+		n += count
+	}
+	return n
+}
+
+// String summarizes the report on one line.
+func (r *Report) String() string {
+	s := fmt.Sprintf("%s: %d lines, %d words, %d comments, %d duplicates, %d rejected",
+		r.File, r.Lines, r.Words, r.Comments, r.Duplicates, r.RejectedLines())
+	if len(r.Rejected) != 0 {
+		var reasons []string
+		for reason, count := range r.Rejected {
+			reasons = append(reasons, fmt.Sprintf("%d %s", count, reason))
+		}
+		sort.Strings(reasons)
+		s += " (" + strings.Join(reasons, ", ") + ")"
+	}
+	return s
+}
+
+// Load reads a list from a file.
+func Load(filename string) ([]Entry, *Report, error) {
+	fp, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer fp.Close()
+	return Read(fp, filename)
+}
+
+// LoadSet reads a list from a file and returns the set of words in it.
+func LoadSet(filename string) (map[string]bool, *Report, error) {
+	entries, report, err := Load(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	return Set(entries), report, nil
+}
+
+// Set returns the set of words in the entries.
+func Set(entries []Entry) map[string]bool {
+	words := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		words[e.Word] = true
+	}
+	return words
+}
+
+// Read reads a list. The name is used in the report and in errors.
+// Compressed input is detected from the gzip header, not the name.
+func Read(r io.Reader, name string) ([]Entry, *Report, error) {
+	return read(r, name, parse)
+}
+
+// LoadFrequencies reads a frequency list from a file.
+func LoadFrequencies(filename string) ([]Entry, *Report, error) {
+	fp, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer fp.Close()
+	return ReadFrequencies(fp, filename)
+}
+
+// ReadFrequencies reads a frequency list, where each line is a word
+// optionally followed by whitespace and a count. The count is stored as
+// the entry's Frequency. Otherwise it is read the same way as Read.
+func ReadFrequencies(r io.Reader, name string) ([]Entry, *Report, error) {
+	return read(r, name, parseFrequency)
+}
+
+// read reads a list, using parse for each line that isn't a comment.
+func read(r io.Reader, name string, parse func(line string) (Entry, string)) ([]Entry, *Report, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+		defer zr.Close()
+		br = bufio.NewReader(zr)
+	}
+
+	report := &Report{File: name, Rejected: make(map[string]int)}
+	var entries []Entry
+	seen := make(map[string]int) // index of each word in entries
+	scanner := bufio.NewScanner(br)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		report.Lines++
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if report.Lines == 1 {
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+		if trimmed := strings.TrimSpace(line); trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			report.Comments++
+			continue
+		}
+
+		e, reason := parse(line)
+		if reason != "" {
+			report.Rejected[reason]++
+			continue
+		} else if i, ok := seen[e.Word]; ok {
+			// a word is only a name if it is never written in lower case
+			if !e.HasTag(TagProper) && !e.HasTag(TagAcronym) {
+				entries[i].Original = e.Original
+				entries[i].Tags = without(entries[i].Tags, TagProper, TagAcronym)
+			}
+			report.Duplicates++
+			continue
+		}
+		seen[e.Word] = len(entries)
+		entries = append(entries, e)
+		report.Words++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return entries, report, nil
+}
+
+// parse returns the entry on a line, or the reason it is rejected.
+func parse(line string) (Entry, string) {
+	columns := strings.Split(line, "\t")
+	var e Entry
+	e.Original = Normalize(strings.TrimSpace(columns[0]))
+	e.Word = strings.ToLower(e.Original)
+
+	letters := 0
+	for _, r := range e.Word {
+		switch {
+		case unicode.IsLetter(r):
+			letters++
+		case unicode.Is(unicode.Mn, r), r == '-', r == '\'':
+			// combining marks, hyphens, and apostrophes are kept but not counted
+		default:
+			return e, RejectCharacters
+		}
+	}
+	if letters < MinLength {
+		return e, RejectTooShort
+	}
+
+	if len(columns) > 1 {
+		e.Source = strings.TrimSpace(columns[1])
+	}
+	if len(columns) > 2 {
+		if value := strings.TrimSpace(columns[2]); value != "" {
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil || f < 0 {
+				return e, RejectFrequency
+			}
+			e.Frequency = f
+		}
+	}
+	if len(columns) > 3 {
+		for _, tag := range strings.Split(columns[3], ",") {
+			if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+				e.Tags = append(e.Tags, tag)
+			}
+		}
+	}
+	for _, tag := range Classify(e.Original) {
+		if !e.HasTag(tag) {
+			e.Tags = append(e.Tags, tag)
+		}
+	}
+	return e, ""
+}
+
+// parseFrequency returns the entry on a frequency list line,
+// or the reason it is rejected.
+func parseFrequency(line string) (Entry, string) {
+	fields := strings.Fields(line)
+	e, reason := parse(fields[0])
+	if reason != "" {
+		return e, reason
+	}
+	if len(fields) > 1 {
+		f, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil || f < 0 {
+			return e, RejectFrequency
+		}
+		e.Frequency = f
+	}
+	return e, ""
+}
+
+// Classify returns the tags for a word from the way it is written.
+// A word with more than one letter in capitals is an acronym;
+// a word starting with a capital otherwise is a proper noun.
+func Classify(word string) []string {
+	var tags []string
+	upper, letters, first := 0, 0, true
+	properNoun := false
+	for _, r := range word {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.IsUpper(r) {
+			upper++
+			properNoun = properNoun || first
+		}
+		first = false
+	}
+	if letters > 1 && upper == letters {
+		tags = append(tags, TagAcronym)
+	} else if properNoun {
+		tags = append(tags, TagProper)
+	}
+	if strings.ContainsRune(word, '-') {
+		tags = append(tags, TagHyphenated)
+	}
+	if strings.ContainsRune(word, '\'') {
+		tags = append(tags, TagApostrophe)
+	}
+	return tags
+}
+
+// HasTag returns true if the entry has the tag.
+func (e Entry) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// without returns the tags other than the ones given.
+func without(tags []string, remove ...string) []string {
+	var kept []string
+	for _, tag := range tags {
+		keep := true
+		for _, r := range remove {
+			keep = keep && tag != r
+		}
+		if keep {
+			kept = append(kept, tag)
+		}
+	}
+	return kept
+}
+
+// Normalize returns a word in Unicode NFC with typographic apostrophes
+// replaced by plain ones. Lists and guesses should both be normalized
+// so that they compare equal.
+func Normalize(word string) string {
+	return norm.NFC.String(apostrophes.Replace(word))
+}
+
+// apostrophes replaces typographic apostrophes with plain ones.
+var apostrophes = strings.NewReplacer("\u2019", "'", "\u02BC", "'")
@@ -0,0 +1,140 @@
+/*
+ * queenie - a spelling bee helper
+ * Copyright (C) 2022 Michael D Henderson
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ *
+ */
+
+package wordlist
+
+import (
+	"bytes"
+	"compress/gzip"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	text := "\uFEFFcotton\r\n" +
+		"# a comment\r\n" +
+		"\r\n" +
+		"count\tdwyl\t12.5\tnoun, Common\r\n" +
+		"Boston\r\n" +
+		"NASA\tacronyms\r\n" +
+		"x-ray\r\n" +
+		"don\u2019t\r\n" +
+		"cafe\u0301\r\n" + // normalized to NFC
+		"cat\r\n" +
+		"c4ts\r\n" +
+		"mouth\t\tlots\r\n" +
+		"cotton\r\n"
+	entries, report, err := Read(strings.NewReader(text), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Entry{
+		{Word: "cotton", Original: "cotton"},
+		{Word: "count", Original: "count", Source: "dwyl", Frequency: 12.5, Tags: []string{"noun", "common"}},
+		{Word: "boston", Original: "Boston", Tags: []string{TagProper}},
+		{Word: "nasa", Original: "NASA", Source: "acronyms", Tags: []string{TagAcronym}},
+		{Word: "x-ray", Original: "x-ray", Tags: []string{TagHyphenated}},
+		{Word: "don't", Original: "don't", Tags: []string{TagApostrophe}},
+		{Word: "caf\u00e9", Original: "caf\u00e9"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entries:\n got %+v\nwant %+v", entries, want)
+	}
+
+	wantReport := &Report{
+		File:       "test",
+		Lines:      13,
+		Words:      7,
+		Comments:   2,
+		Duplicates: 1,
+		Rejected:   map[string]int{RejectTooShort: 1, RejectCharacters: 1, RejectFrequency: 1},
+	}
+	if !reflect.DeepEqual(report, wantReport) {
+		t.Errorf("report:\n got %+v\nwant %+v", report, wantReport)
+	}
+	if got := report.RejectedLines(); got != 3 {
+		t.Errorf("rejected lines: got %d, want 3", got)
+	}
+}
+
+func TestReadGzip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte("# compressed\ncotton\ncount\n"))
+	zw.Close()
+
+	// compression is detected from the content, not the name
+	entries, report, err := Read(&buf, "words.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Set(entries); !reflect.DeepEqual(got, map[string]bool{"cotton": true, "count": true}) {
+		t.Errorf("words: got %v", got)
+	}
+	if report.Lines != 3 || report.Words != 2 || report.Comments != 1 {
+		t.Errorf("report: got %s", report)
+	}
+}
+
+func TestReadDuplicates(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		text     string
+		original string
+		tags     []string
+	}{
+		{"proper then lower case", "Mark\nmark\n", "mark", nil},
+		{"lower case then proper", "mark\nMark\n", "mark", nil},
+		{"acronym then lower case", "NASA\nnasa\n", "nasa", nil},
+		{"proper only", "Boston\nBOSTON\n", "Boston", []string{TagProper}},
+		{"other tags are kept", "Jack-o\njack-o\n", "jack-o", []string{TagHyphenated}},
+	} {
+		entries, report, err := Read(strings.NewReader(tc.text), tc.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || report.Duplicates != 1 {
+			t.Errorf("%s: got %d entries and %d duplicates, want 1 and 1", tc.name, len(entries), report.Duplicates)
+			continue
+		}
+		if e := entries[0]; e.Original != tc.original || !reflect.DeepEqual(e.Tags, tc.tags) {
+			t.Errorf("%s: got %q %q, want %q %q", tc.name, e.Original, e.Tags, tc.original, tc.tags)
+		}
+	}
+}
+
+func TestReadFrequencies(t *testing.T) {
+	text := "# word count\r\nthe 100\r\ncotton 12\ncount\t7.5\nmouth -1\ncat 3\n"
+	entries, report, err := ReadFrequencies(strings.NewReader(text), "freq")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]float64)
+	for _, e := range entries {
+		got[e.Word] = e.Frequency
+	}
+	if want := map[string]float64{"cotton": 12, "count": 7.5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("frequencies: got %v, want %v", got, want)
+	}
+	if report.Comments != 1 || report.Rejected[RejectTooShort] != 2 || report.Rejected[RejectFrequency] != 1 {
+		t.Errorf("report: got %s", report)
+	}
+}
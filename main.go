@@ -20,13 +20,15 @@
 // Package main implements the entry point for the Queenie server.
 package main
 
+//go:generate go run . codegen
+
 import (
 	"fmt"
 	"github.com/mdhender/queenie/cmd"
+	"github.com/mdhender/queenie/internal/wordlist"
 	"log"
 	"math/rand"
 	"net/http"
-	"os"
 	"sort"
 	"strings"
 	"time"
@@ -146,21 +148,8 @@ func (s *server) index() http.Handler {
 }
 
 func loadwords(filename string) (map[string]bool, error) {
-	raw, err := os.ReadFile(filename)
-	if err != nil {
-		return nil, err
-	}
-
-	words := make(map[string]bool)
-	for _, word := range strings.Split(string(raw), "\n") {
-		// must be at least four characters
-		if len(word) < 4 {
-			continue
-		}
-		words[strings.ToLower(word)] = true
-	}
-
-	return words, nil
+	words, _, err := wordlist.LoadSet(filename)
+	return words, err
 }
 
 func printmap(words []string, f func(word string)) {